@@ -0,0 +1,219 @@
+package chanx
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// SlowConsumerPolicy 定义 Broadcaster 在订阅者缓冲区已满时的处理策略。
+type SlowConsumerPolicy int
+
+const (
+	// BlockSlow 等待慢订阅者接收，所有订阅者都会被阻塞。
+	BlockSlow SlowConsumerPolicy = iota
+	// DropSlow 丢弃发给慢订阅者的消息，并计入丢弃数量。
+	DropSlow
+	// DisconnectSlow 断开慢订阅者，关闭其通道。
+	DisconnectSlow
+)
+
+// Broadcaster 将输入通道中的每个值广播给所有订阅者。
+// 每个订阅者拥有独立的缓冲区和慢消费者策略，可以随时加入或离开。
+// 没有订阅者时收到的值会被丢弃。输入通道关闭或上下文取消时，所有订阅者的通道被关闭。
+type Broadcaster[T any] struct {
+	ctx     context.Context
+	in      <-chan T
+	mu      sync.Mutex
+	subs    map[*Subscriber[T]]struct{}
+	closed  bool
+	dropped atomic.Uint64
+}
+
+// NewBroadcaster 创建一个 Broadcaster 并开始广播输入通道中的值。
+func NewBroadcaster[T any](ctx context.Context, in <-chan T) *Broadcaster[T] {
+	b := newBroadcaster(ctx, in)
+	go b.loop()
+	return b
+}
+
+func newBroadcaster[T any](ctx context.Context, in <-chan T) *Broadcaster[T] {
+	return &Broadcaster[T]{
+		ctx:  ctx,
+		in:   in,
+		subs: make(map[*Subscriber[T]]struct{}),
+	}
+}
+
+// Subscribe 添加一个订阅者，buffer 为订阅者通道的缓冲区大小，policy 为缓冲区满时的处理策略。
+// 如果 Broadcaster 已经结束，返回的订阅者通道已关闭。
+func (b *Broadcaster[T]) Subscribe(buffer int, policy SlowConsumerPolicy) *Subscriber[T] {
+	if buffer < 0 {
+		buffer = 0
+	}
+	s := &Subscriber[T]{
+		b:      b,
+		c:      make(chan T, buffer),
+		done:   make(chan struct{}),
+		policy: policy,
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		s.close()
+		return s
+	}
+	b.subs[s] = struct{}{}
+	return s
+}
+
+// Len 返回当前订阅者的数量。
+func (b *Broadcaster[T]) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+// Dropped 返回所有订阅者丢弃的消息总数。
+func (b *Broadcaster[T]) Dropped() uint64 {
+	return b.dropped.Load()
+}
+
+func (b *Broadcaster[T]) loop() {
+	defer b.closeAll()
+	for {
+		select {
+		case <-b.ctx.Done():
+			return
+		case v, ok := <-b.in:
+			if !ok {
+				return
+			}
+			for _, s := range b.snapshot() {
+				if !s.send(b.ctx, v) {
+					b.remove(s)
+				}
+			}
+		}
+	}
+}
+
+func (b *Broadcaster[T]) snapshot() []*Subscriber[T] {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subs := make([]*Subscriber[T], 0, len(b.subs))
+	for s := range b.subs {
+		subs = append(subs, s)
+	}
+	return subs
+}
+
+func (b *Broadcaster[T]) remove(s *Subscriber[T]) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subs, s)
+}
+
+func (b *Broadcaster[T]) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for s := range b.subs {
+		s.close()
+		delete(b.subs, s)
+	}
+}
+
+// Subscriber 是 Broadcaster 的一个订阅者。
+type Subscriber[T any] struct {
+	b        *Broadcaster[T]
+	c        chan T
+	policy   SlowConsumerPolicy
+	mu       sync.Mutex
+	closed   bool
+	done     chan struct{}
+	doneOnce sync.Once
+	dropped  atomic.Uint64
+}
+
+// C 返回订阅者接收消息的通道。
+func (s *Subscriber[T]) C() <-chan T {
+	return s.c
+}
+
+// Dropped 返回该订阅者因缓冲区已满而丢弃的消息数量。
+func (s *Subscriber[T]) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Unsubscribe 取消订阅并关闭订阅者的通道，可以重复调用。
+func (s *Subscriber[T]) Unsubscribe() {
+	s.doneOnce.Do(func() { close(s.done) })
+	s.close()
+	s.b.remove(s)
+}
+
+// send 按照策略向订阅者发送 v，返回 false 表示订阅者应被移除。
+func (s *Subscriber[T]) send(ctx context.Context, v T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	switch s.policy {
+	case DropSlow:
+		select {
+		case s.c <- v:
+		default:
+			s.dropped.Add(1)
+			s.b.dropped.Add(1)
+		}
+		return true
+	case DisconnectSlow:
+		select {
+		case s.c <- v:
+			return true
+		default:
+			s.dropped.Add(1)
+			s.b.dropped.Add(1)
+			s.closeLocked()
+			return false
+		}
+	default:
+		select {
+		case s.c <- v:
+		case <-s.done:
+		case <-ctx.Done():
+		}
+		return true
+	}
+}
+
+func (s *Subscriber[T]) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeLocked()
+}
+
+func (s *Subscriber[T]) closeLocked() {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.c)
+}
+
+// Tee 将输入通道中的每个值复制到 n 个输出通道中，每个输出通道的缓冲区大小为 buffer。
+// 与 FanOut 不同，返回的通道可以被接收，发送时等待最慢的输出通道。n 小于等于 0 时按 1 处理。
+func Tee[T any](ctx context.Context, in <-chan T, n int, buffer int) []<-chan T {
+	if n <= 0 {
+		n = 1
+	}
+	b := newBroadcaster(ctx, in)
+	outs := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = b.Subscribe(buffer, BlockSlow).C()
+	}
+	go b.loop()
+	return outs
+}
@@ -0,0 +1,116 @@
+package chanx
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTee(t *testing.T) {
+	ctx := context.Background()
+	outs := Tee(ctx, Emit(ctx, 1, 2, 3), 3, 0)
+	var wg sync.WaitGroup
+	results := make([][]int, len(outs))
+	for i, out := range outs {
+		wg.Add(1)
+		go func(i int, out <-chan int) {
+			defer wg.Done()
+			results[i] = collect(out)
+		}(i, out)
+	}
+	wg.Wait()
+	for i, got := range results {
+		if !reflect.DeepEqual(got, []int{1, 2, 3}) {
+			t.Errorf("output %d: unexpected values %v", i, got)
+		}
+	}
+}
+
+func TestTeeNonPositive(t *testing.T) {
+	ctx := context.Background()
+	outs := Tee(ctx, Emit(ctx, 1, 2), -1, 0)
+	if len(outs) != 1 {
+		t.Fatalf("expected 1 output, got %d", len(outs))
+	}
+	if got := collect(outs[0]); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("unexpected values %v", got)
+	}
+}
+
+func TestBroadcasterDropSlow(t *testing.T) {
+	ctx := context.Background()
+	in := make(chan int)
+	b := NewBroadcaster(ctx, in)
+	fast := b.Subscribe(10, BlockSlow)
+	slow := b.Subscribe(1, DropSlow)
+	for i := 0; i < 5; i++ {
+		in <- i
+	}
+	close(in)
+	if got := collect(fast.C()); len(got) != 5 {
+		t.Errorf("fast subscriber: unexpected values %v", got)
+	}
+	if got := collect(slow.C()); !reflect.DeepEqual(got, []int{0}) {
+		t.Errorf("slow subscriber: unexpected values %v", got)
+	}
+	if d := slow.Dropped(); d != 4 {
+		t.Errorf("expected 4 dropped, got %d", d)
+	}
+	if d := b.Dropped(); d != 4 {
+		t.Errorf("expected 4 dropped in total, got %d", d)
+	}
+}
+
+func TestBroadcasterDisconnectSlow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in := make(chan int)
+	b := NewBroadcaster(ctx, in)
+	slow := b.Subscribe(1, DisconnectSlow)
+	in <- 1
+	in <- 2
+	// 确保值 2 已被处理
+	in <- 3
+	if got := collect(slow.C()); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("unexpected values %v", got)
+	}
+	if n := b.Len(); n != 0 {
+		t.Errorf("expected no subscribers, got %d", n)
+	}
+}
+
+func TestBroadcasterUnsubscribe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in := make(chan int)
+	b := NewBroadcaster(ctx, in)
+	s := b.Subscribe(0, BlockSlow)
+	in <- 1
+	// 订阅者未接收，广播被阻塞，取消订阅后应解除阻塞
+	time.Sleep(10 * time.Millisecond)
+	s.Unsubscribe()
+	s.Unsubscribe()
+	select {
+	case in <- 2:
+	case <-time.After(time.Second):
+		t.Fatal("broadcaster still blocked after unsubscribe")
+	}
+	if n := b.Len(); n != 0 {
+		t.Errorf("expected no subscribers, got %d", n)
+	}
+	Discard(s.C())
+}
+
+func TestBroadcasterSubscribeAfterClose(t *testing.T) {
+	ctx := context.Background()
+	in := make(chan int)
+	b := NewBroadcaster(ctx, in)
+	close(in)
+	time.Sleep(10 * time.Millisecond)
+	s := b.Subscribe(1, BlockSlow)
+	if _, ok := <-s.C(); ok {
+		t.Error("expected closed channel")
+	}
+}
@@ -0,0 +1,135 @@
+package chanx
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// BufferStats 是 Buffer 的统计快照。
+type BufferStats struct {
+	// Capacity 缓冲区的容量
+	Capacity int
+	// Depth 当前在缓冲区中等待被消费的元素数量
+	Depth int
+	// Enqueued 进入缓冲区的元素总数
+	Enqueued uint64
+	// Dequeued 被消费者取走的元素总数
+	Dequeued uint64
+	// WaitTime 元素从进入缓冲区到被消费者取走的总等待时长
+	WaitTime time.Duration
+	// FullTime 缓冲区处于已满状态的总时长，期间 Buffer 不再从输入通道接收元素。
+	// Buffer 无法观察到生产者，FullTime 只说明生产者可能被阻塞，不等于生产者实际被阻塞的时长
+	FullTime time.Duration
+}
+
+// AvgWaitTime 返回每个元素的平均等待时长。
+func (s BufferStats) AvgWaitTime() time.Duration {
+	if s.Dequeued == 0 {
+		return 0
+	}
+	return s.WaitTime / time.Duration(s.Dequeued)
+}
+
+// Buffer 在输入通道和消费者之间插入一个固定大小的缓冲区，并记录缓冲区的统计数据，
+// 用于发现管道中的瓶颈：Depth 持续接近 Capacity 且 FullTime 增长说明下游是瓶颈，
+// Depth 持续为 0 说明上游是瓶颈。
+type Buffer[T any] struct {
+	out      chan T
+	capacity int
+	enqueued atomic.Uint64
+	dequeued atomic.Uint64
+	waitTime atomic.Int64
+	fullTime atomic.Int64
+}
+
+type bufferItem[T any] struct {
+	value T
+	at    time.Time
+}
+
+// NewBuffer 创建一个大小为 size 的 Buffer，从输入通道接收元素，通过 Out 输出，size 小于等于 0 时为 1。
+// 缓冲区中最多同时保存 size 个元素，Stats 的 Depth 不会超过 Capacity。
+// 输入通道关闭后，缓冲区中剩余的元素全部输出后 Out 被关闭；上下文取消时 Out 立即被关闭。
+func NewBuffer[T any](ctx context.Context, in <-chan T, size int) *Buffer[T] {
+	if size <= 0 {
+		size = 1
+	}
+	b := &Buffer[T]{
+		out:      make(chan T),
+		capacity: size,
+	}
+	clock := ClockFrom(ctx)
+	go func() {
+		defer close(b.out)
+		queue := make([]bufferItem[T], 0, size)
+		closed := false
+		// fullSince 缓冲区最近一次被填满的时间
+		var fullSince time.Time
+		for {
+			// 缓冲区已满时不再接收
+			var recv <-chan T
+			if !closed && len(queue) < size {
+				recv = in
+			}
+			var send chan T
+			var head T
+			if len(queue) > 0 {
+				send = b.out
+				head = queue[0].value
+			}
+			if closed && send == nil {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-recv:
+				if !ok {
+					closed = true
+					continue
+				}
+				now := clock.Now()
+				queue = append(queue, bufferItem[T]{value: v, at: now})
+				b.enqueued.Add(1)
+				if len(queue) == size {
+					fullSince = now
+				}
+			case send <- head:
+				now := clock.Now()
+				if len(queue) == size && !closed {
+					b.fullTime.Add(int64(now.Sub(fullSince)))
+				}
+				item := queue[0]
+				queue[0] = bufferItem[T]{}
+				queue = queue[1:]
+				b.dequeued.Add(1)
+				b.waitTime.Add(int64(now.Sub(item.at)))
+			}
+		}
+	}()
+	return b
+}
+
+// Out 返回输出通道。
+func (b *Buffer[T]) Out() <-chan T {
+	return b.out
+}
+
+// Stats 返回当前的统计快照。
+func (b *Buffer[T]) Stats() BufferStats {
+	dequeued := b.dequeued.Load()
+	enqueued := b.enqueued.Load()
+	depth := 0
+	if enqueued > dequeued {
+		depth = int(enqueued - dequeued)
+	}
+	return BufferStats{
+		Capacity: b.capacity,
+		Depth:    depth,
+		Enqueued: enqueued,
+		Dequeued: dequeued,
+		WaitTime: time.Duration(b.waitTime.Load()),
+		FullTime: time.Duration(b.fullTime.Load()),
+	}
+}
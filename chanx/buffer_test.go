@@ -0,0 +1,61 @@
+package chanx
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBuffer(t *testing.T) {
+	ctx := context.Background()
+	in := make(chan int)
+	b := NewBuffer(ctx, in, 2)
+	go func() {
+		defer close(in)
+		for i := 0; i < 5; i++ {
+			in <- i
+		}
+	}()
+	// 没有消费者时，缓冲区被填满，生产者被阻塞
+	deadline := time.Now().Add(time.Second)
+	for b.Stats().Depth < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	stats := b.Stats()
+	if stats.Capacity != 2 || stats.Enqueued != 2 || stats.Dequeued != 0 || stats.Depth != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	var got []int
+	for v := range b.Out() {
+		got = append(got, v)
+	}
+	if len(got) != 5 {
+		t.Fatalf("expected 5 values, got %v", got)
+	}
+	for i, v := range got {
+		if v != i {
+			t.Errorf("expected %d at %d, got %d", i, i, v)
+		}
+	}
+	stats = b.Stats()
+	if stats.Enqueued != 5 || stats.Dequeued != 5 || stats.Depth != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if stats.FullTime < 20*time.Millisecond {
+		t.Errorf("expected full time, got %v", stats.FullTime)
+	}
+	if stats.WaitTime < 20*time.Millisecond || stats.AvgWaitTime() <= 0 {
+		t.Errorf("expected wait time, got %v", stats.WaitTime)
+	}
+}
+
+func TestBufferCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	b := NewBuffer(ctx, in, 1)
+	cancel()
+	if _, ok := <-b.Out(); ok {
+		t.Error("expected closed channel")
+	}
+}
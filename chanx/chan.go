@@ -3,7 +3,6 @@ package chanx
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"reflect"
 	"runtime"
 	"sync"
@@ -14,10 +13,10 @@ import (
 
 var ErrDefaultBranch = fmt.Errorf("chanx: default branch")
 
-var r *rand.Rand
+var r Rand
 
 func init() {
-	r = rand.New(rand.NewSource(time.Now().UnixNano()))
+	r = NewLockedRand(time.Now().UnixNano())
 }
 
 // All 将多个输入通道合并为一个输出通道，然后返回一个包含所有输入通道当前值的切片的通道
@@ -27,6 +26,7 @@ func All[T any](ctx context.Context, ins ...<-chan T) <-chan []T {
 }
 
 // Any 从多个输入通道中任意选择一个值，返回一个包含此值的一个通道，
+// 选择顺序由上下文中的 Rand 决定，参见 WithRand。
 func Any[T any](ctx context.Context, ins ...<-chan T) <-chan T {
 	out := make(chan T, 1)
 	go _any(ctx, out, ins...)
@@ -37,9 +37,10 @@ func Any[T any](ctx context.Context, ins ...<-chan T) <-chan T {
 func _any[T any](ctx context.Context, out chan T, ins ...<-chan T) {
 	// 确保在函数退出时关闭 out 和 errC 通道。
 	defer close(out)
+	rnd := RandFrom(ctx)
 	for len(ins) > 0 {
 		// 当 ins 列表不为空时，随机选择一个通道 ch。
-		sel := r.Intn(len(ins))
+		sel := rnd.Intn(len(ins))
 		ch := ins[sel]
 		select {
 		case <-ctx.Done():
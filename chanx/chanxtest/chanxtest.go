@@ -0,0 +1,185 @@
+// Package chanxtest 提供了用于编写可重复的 chanx 测试的工具，
+// 包括固定种子的随机数源、只在手动推进时才前进的模拟时钟以及故障注入。
+package chanxtest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/soyacen/goconc/chanx"
+)
+
+var _ chanx.Clock = (*Clock)(nil)
+
+// Context 返回一个注入了模拟时钟和以 seed 为种子的随机数源的上下文，以及该模拟时钟。
+// 使用该上下文运行的 chanx 算子，其选择顺序和时间行为都是可重复的。
+func Context(parent context.Context, seed int64) (context.Context, *Clock) {
+	clock := NewClock(time.Unix(0, 0))
+	ctx := chanx.WithClock(parent, clock)
+	ctx = chanx.WithRand(ctx, chanx.NewLockedRand(seed))
+	return ctx, clock
+}
+
+// Clock 是一个模拟时钟，时间只在调用 Advance 或 Set 时前进。
+type Clock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*waiter
+}
+
+// waiter 表示一个等待触发的 Timer 或 Ticker。
+type waiter struct {
+	clock  *Clock
+	c      chan time.Time
+	when   time.Time
+	period time.Duration
+	active bool
+}
+
+// NewClock 创建一个从 start 开始的模拟时钟。
+func NewClock(start time.Time) *Clock {
+	c := &Clock{now: start}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now 返回模拟时钟的当前时间。
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer 创建一个在模拟时间经过 d 之后触发的 Timer。
+func (c *Clock) NewTimer(d time.Duration) chanx.Timer {
+	w := &waiter{clock: c, c: make(chan time.Time, 1)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schedule(w, d, 0)
+	return &timer{w}
+}
+
+// NewTicker 创建一个每隔模拟时间 d 触发一次的 Ticker。
+func (c *Clock) NewTicker(d time.Duration) chanx.Ticker {
+	if d <= 0 {
+		panic("chanxtest: non-positive interval for NewTicker")
+	}
+	w := &waiter{clock: c, c: make(chan time.Time, 1)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.schedule(w, d, d)
+	return &ticker{w}
+}
+
+// Advance 将模拟时钟向前推进 d，按时间顺序触发期间到期的所有 Timer 和 Ticker。
+func (c *Clock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set 将模拟时钟设置为 t，按时间顺序触发期间到期的所有 Timer 和 Ticker。t 早于当前时间时不做任何事。
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for {
+		w := c.next()
+		if w == nil || w.when.After(t) {
+			break
+		}
+		c.now = w.when
+		select {
+		case w.c <- w.when:
+		default:
+		}
+		if w.period > 0 {
+			w.when = w.when.Add(w.period)
+		} else {
+			c.remove(w)
+		}
+	}
+	if t.After(c.now) {
+		c.now = t
+	}
+}
+
+// BlockUntil 阻塞直到至少有 n 个 Timer 或 Ticker 在等待触发。
+// 用于在推进时间之前，确保被测试的算子已经开始等待。
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+// Waiters 返回正在等待触发的 Timer 和 Ticker 的数量。
+func (c *Clock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// schedule 安排 w 在 d 之后触发，调用时必须持有锁。
+func (c *Clock) schedule(w *waiter, d time.Duration, period time.Duration) {
+	w.when = c.now.Add(d)
+	w.period = period
+	if !w.active {
+		w.active = true
+		c.waiters = append(c.waiters, w)
+	}
+	c.cond.Broadcast()
+}
+
+// next 返回最早到期的 waiter，调用时必须持有锁。
+func (c *Clock) next() *waiter {
+	if len(c.waiters) == 0 {
+		return nil
+	}
+	sort.SliceStable(c.waiters, func(i, j int) bool { return c.waiters[i].when.Before(c.waiters[j].when) })
+	return c.waiters[0]
+}
+
+// remove 移除 w，返回 w 是否处于等待状态，调用时必须持有锁。
+func (c *Clock) remove(w *waiter) bool {
+	if !w.active {
+		return false
+	}
+	w.active = false
+	for i, v := range c.waiters {
+		if v == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+type timer struct{ w *waiter }
+
+func (t *timer) C() <-chan time.Time { return t.w.c }
+
+func (t *timer) Stop() bool {
+	t.w.clock.mu.Lock()
+	defer t.w.clock.mu.Unlock()
+	return t.w.clock.remove(t.w)
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	t.w.clock.mu.Lock()
+	defer t.w.clock.mu.Unlock()
+	active := t.w.active
+	t.w.clock.schedule(t.w, d, 0)
+	return active
+}
+
+type ticker struct{ w *waiter }
+
+func (t *ticker) C() <-chan time.Time { return t.w.c }
+
+func (t *ticker) Stop() {
+	t.w.clock.mu.Lock()
+	defer t.w.clock.mu.Unlock()
+	t.w.clock.remove(t.w)
+}
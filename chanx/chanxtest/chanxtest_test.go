@@ -0,0 +1,117 @@
+package chanxtest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/soyacen/goconc/chanx"
+)
+
+func TestClockTimer(t *testing.T) {
+	clock := NewClock(time.Unix(0, 0))
+	timer := clock.NewTimer(time.Second)
+	clock.Advance(500 * time.Millisecond)
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired too early")
+	default:
+	}
+	clock.Advance(500 * time.Millisecond)
+	select {
+	case now := <-timer.C():
+		if !now.Equal(time.Unix(1, 0)) {
+			t.Errorf("unexpected fire time %v", now)
+		}
+	default:
+		t.Fatal("timer not fired")
+	}
+	if timer.Stop() {
+		t.Error("expected Stop to report fired timer")
+	}
+}
+
+func TestClockTicker(t *testing.T) {
+	clock := NewClock(time.Unix(0, 0))
+	ticker := clock.NewTicker(time.Second)
+	defer ticker.Stop()
+	for i := 1; i <= 3; i++ {
+		clock.Advance(time.Second)
+		if now := <-ticker.C(); !now.Equal(time.Unix(int64(i), 0)) {
+			t.Errorf("unexpected tick time %v", now)
+		}
+	}
+}
+
+func TestDebounceWithSimulatedTime(t *testing.T) {
+	ctx, clock := Context(context.Background(), 1)
+	in := make(chan int)
+	out := chanx.Debounce(ctx, in, time.Minute)
+	in <- 1
+	clock.BlockUntil(1)
+	clock.Advance(time.Minute)
+	if v := <-out; v != 1 {
+		t.Errorf("expected 1, got %d", v)
+	}
+	in <- 2
+	clock.BlockUntil(1)
+	clock.Advance(30 * time.Second)
+	select {
+	case v := <-out:
+		t.Fatalf("unexpected value %d before quiet period", v)
+	default:
+	}
+	close(in)
+	if v := <-out; v != 2 {
+		t.Errorf("expected pending value 2, got %d", v)
+	}
+	if _, ok := <-out; ok {
+		t.Error("expected closed channel")
+	}
+}
+
+func TestAnyWithFixedSeed(t *testing.T) {
+	run := func() []int {
+		ctx, _ := Context(context.Background(), 42)
+		var got []int
+		for i := 0; i < 10; i++ {
+			ins := make([]<-chan int, 5)
+			for j := range ins {
+				ins[j] = chanx.Once(j)
+			}
+			got = append(got, <-chanx.Any(ctx, ins...))
+		}
+		return got
+	}
+	first, second := run(), run()
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("expected same selection order, got %v and %v", first, second)
+	}
+}
+
+func TestFaulty(t *testing.T) {
+	run := func() []error {
+		f := Faulty(chanx.NewLockedRand(7), 50, func(v int) (int, error) { return v, nil })
+		var errs []error
+		for i := 0; i < 20; i++ {
+			_, err := f(i)
+			errs = append(errs, err)
+		}
+		return errs
+	}
+	first, second := run(), run()
+	if !reflect.DeepEqual(first, second) {
+		t.Error("expected reproducible faults")
+	}
+	injected := 0
+	for _, err := range first {
+		if errors.Is(err, ErrInjected) {
+			injected++
+		}
+	}
+	if injected == 0 || injected == len(first) {
+		t.Errorf("unexpected number of injected faults: %d", injected)
+	}
+}
@@ -0,0 +1,21 @@
+package chanxtest
+
+import (
+	"errors"
+
+	"github.com/soyacen/goconc/chanx"
+)
+
+var ErrInjected = errors.New("chanxtest: injected fault")
+
+// Faulty 包装 f，每次调用时以 percent% 的概率不调用 f 而直接返回 ErrInjected。
+// 是否注入故障由 rnd 决定，使用固定种子的 rnd 时，故障出现的位置是可重复的。
+func Faulty[T any, R any](rnd chanx.Rand, percent int, f func(T) (R, error)) func(T) (R, error) {
+	return func(value T) (R, error) {
+		if rnd.Intn(100) < percent {
+			var zero R
+			return zero, ErrInjected
+		}
+		return f(value)
+	}
+}
@@ -0,0 +1,101 @@
+package chanx
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Clock 抽象了 chanx 中与时间相关的操作。
+// 通过 WithClock 将 Clock 注入上下文后，使用该上下文的算子都会使用注入的 Clock，从而可以在测试中模拟时间。
+type Clock interface {
+	// Now 返回当前时间
+	Now() time.Time
+	// NewTimer 创建一个在 d 之后触发的 Timer
+	NewTimer(d time.Duration) Timer
+	// NewTicker 创建一个每隔 d 触发一次的 Ticker
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer 对应 time.Timer。
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker 对应 time.Ticker。
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Rand 是 chanx 中在多个输入通道之间进行选择时使用的随机数源。
+// 通过 WithRand 将 Rand 注入上下文后，使用该上下文的算子都会使用注入的 Rand，实现必须是并发安全的。
+type Rand interface {
+	// Intn 返回 [0, n) 范围内的随机数
+	Intn(n int) int
+}
+
+type clockKey struct{}
+
+type randKey struct{}
+
+// WithClock 返回一个携带 clock 的上下文。
+func WithClock(ctx context.Context, clock Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, clock)
+}
+
+// WithRand 返回一个携带随机数源 rnd 的上下文。
+func WithRand(ctx context.Context, rnd Rand) context.Context {
+	return context.WithValue(ctx, randKey{}, rnd)
+}
+
+// ClockFrom 返回上下文中的 Clock，没有注入时返回基于 time 包的真实时钟。
+func ClockFrom(ctx context.Context) Clock {
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok {
+		return clock
+	}
+	return realClock{}
+}
+
+// RandFrom 返回上下文中的 Rand，没有注入时返回以当前时间为种子的全局随机数源。
+func RandFrom(ctx context.Context) Rand {
+	if rnd, ok := ctx.Value(randKey{}).(Rand); ok {
+		return rnd
+	}
+	return r
+}
+
+// NewLockedRand 返回一个以 seed 为种子、并发安全的 Rand。
+func NewLockedRand(seed int64) Rand {
+	return &lockedRand{r: rand.New(rand.NewSource(seed))}
+}
+
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func (l *lockedRand) Intn(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Intn(n)
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+
+func (realClock) NewTicker(d time.Duration) Ticker { return realTicker{time.NewTicker(d)} }
+
+type realTimer struct{ *time.Timer }
+
+func (t realTimer) C() <-chan time.Time { return t.Timer.C }
+
+type realTicker struct{ *time.Ticker }
+
+func (t realTicker) C() <-chan time.Time { return t.Ticker.C }
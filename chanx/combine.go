@@ -0,0 +1,156 @@
+package chanx
+
+import (
+	"container/heap"
+	"context"
+	"reflect"
+)
+
+// Zip 依次从每个输入通道中各接收一个值，组成一个切片输出，第 n 个切片包含每个输入通道的第 n 个值。
+// 任意一个输入通道关闭或上下文取消时，输出通道被关闭。
+func Zip[T any](ctx context.Context, ins ...<-chan T) <-chan []T {
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		if len(ins) == 0 {
+			return
+		}
+		for {
+			values := make([]T, len(ins))
+			for i, in := range ins {
+				select {
+				case <-ctx.Done():
+					return
+				case value, ok := <-in:
+					if !ok {
+						return
+					}
+					values[i] = value
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- values:
+			}
+		}
+	}()
+	return out
+}
+
+// CombineLatest 在任意一个输入通道收到新值时，输出由每个输入通道最近一个值组成的切片。
+// 在所有输入通道都至少收到一个值之前不输出。所有输入通道关闭或上下文取消时，输出通道被关闭。
+func CombineLatest[T any](ctx context.Context, ins ...<-chan T) <-chan []T {
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		// 最后一个 case 用于监听上下文取消
+		cases := make([]reflect.SelectCase, len(ins)+1)
+		for i, in := range ins {
+			cases[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(in)}
+		}
+		cases[len(ins)] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+		latest := make([]T, len(ins))
+		received := make([]bool, len(ins))
+		missing := len(ins)
+		remaining := len(ins)
+		for remaining > 0 {
+			chosen, recv, ok := reflect.Select(cases)
+			if chosen == len(ins) {
+				return
+			}
+			if !ok {
+				// 通道已关闭，不再监听该通道
+				cases[chosen].Chan = reflect.Value{}
+				remaining--
+				continue
+			}
+			latest[chosen], _ = recv.Interface().(T)
+			if !received[chosen] {
+				received[chosen] = true
+				missing--
+			}
+			if missing > 0 {
+				continue
+			}
+			values := make([]T, len(latest))
+			copy(values, latest)
+			select {
+			case <-ctx.Done():
+				return
+			case out <- values:
+			}
+		}
+	}()
+	return out
+}
+
+// MergeSorted 将多个已按 cmp 升序排列的输入通道合并为一个按 cmp 升序排列的输出通道。
+// 所有输入通道关闭或上下文取消时，输出通道被关闭。
+func MergeSorted[T any](ctx context.Context, cmp func(a, b T) int, ins ...<-chan T) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		h := &mergeHeap[T]{cmp: cmp}
+		// receive 从第 i 个输入通道接收下一个值并放入堆中，返回 false 表示上下文已取消
+		receive := func(i int) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case value, ok := <-ins[i]:
+				if ok {
+					heap.Push(h, mergeItem[T]{value: value, index: i})
+				}
+				return true
+			}
+		}
+		for i := range ins {
+			if !receive(i) {
+				return
+			}
+		}
+		for h.Len() > 0 {
+			item := heap.Pop(h).(mergeItem[T])
+			select {
+			case <-ctx.Done():
+				return
+			case out <- item.value:
+			}
+			if !receive(item.index) {
+				return
+			}
+		}
+	}()
+	return out
+}
+
+type mergeItem[T any] struct {
+	value T
+	index int
+}
+
+// mergeHeap 实现了 heap.Interface，值相等时按输入通道的下标排序，保证合并结果稳定。
+type mergeHeap[T any] struct {
+	items []mergeItem[T]
+	cmp   func(a, b T) int
+}
+
+func (h *mergeHeap[T]) Len() int { return len(h.items) }
+
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	if c := h.cmp(h.items[i].value, h.items[j].value); c != 0 {
+		return c < 0
+	}
+	return h.items[i].index < h.items[j].index
+}
+
+func (h *mergeHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *mergeHeap[T]) Push(x any) { h.items = append(h.items, x.(mergeItem[T])) }
+
+func (h *mergeHeap[T]) Pop() any {
+	n := len(h.items)
+	item := h.items[n-1]
+	h.items = h.items[:n-1]
+	return item
+}
@@ -0,0 +1,55 @@
+package chanx
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestZip(t *testing.T) {
+	ctx := context.Background()
+	got := collect(Zip(ctx, Emit(ctx, 1, 2, 3), Emit(ctx, 10, 20)))
+	want := [][]int{{1, 10}, {2, 20}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestCombineLatest(t *testing.T) {
+	ctx := context.Background()
+	a := make(chan int)
+	b := make(chan int)
+	out := CombineLatest[int](ctx, a, b)
+	a <- 1
+	b <- 10
+	if v := <-out; !reflect.DeepEqual(v, []int{1, 10}) {
+		t.Errorf("unexpected value %v", v)
+	}
+	a <- 2
+	if v := <-out; !reflect.DeepEqual(v, []int{2, 10}) {
+		t.Errorf("unexpected value %v", v)
+	}
+	close(a)
+	b <- 20
+	if v := <-out; !reflect.DeepEqual(v, []int{2, 20}) {
+		t.Errorf("unexpected value %v", v)
+	}
+	close(b)
+	if _, ok := <-out; ok {
+		t.Error("expected closed channel")
+	}
+}
+
+func TestMergeSorted(t *testing.T) {
+	ctx := context.Background()
+	got := collect(MergeSorted(ctx, func(a, b int) int { return a - b },
+		Emit(ctx, 1, 4, 7, 10),
+		Emit(ctx, 2, 5, 8),
+		Emit[int](ctx),
+		Emit(ctx, 3, 6, 9, 11, 12),
+	))
+	want := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
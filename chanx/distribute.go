@@ -0,0 +1,110 @@
+package chanx
+
+import (
+	"context"
+	"hash/maphash"
+	"reflect"
+)
+
+// DistributeRoundRobin 将输入通道中的元素依次轮流分发到 n 个输出通道中，每个元素只会发送到一个输出通道。
+// 输入通道关闭或上下文取消时，所有输出通道被关闭。
+func DistributeRoundRobin[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	if n <= 0 {
+		n = 1
+	}
+	next := 0
+	return distribute(ctx, in, n, func(T) int {
+		i := next
+		next = (next + 1) % n
+		return i
+	})
+}
+
+// DistributeByKey 按照 key 的哈希值将输入通道中的元素分发到 n 个输出通道中，
+// 相同 key 的元素总是发送到同一个输出通道，从而保证相同 key 的元素的顺序。
+// 输入通道关闭或上下文取消时，所有输出通道被关闭。
+func DistributeByKey[T any](ctx context.Context, in <-chan T, n int, key func(T) string) []<-chan T {
+	if n <= 0 {
+		n = 1
+	}
+	seed := maphash.MakeSeed()
+	return distribute(ctx, in, n, func(value T) int {
+		return int(maphash.String(seed, key(value)) % uint64(n))
+	})
+}
+
+// DistributeFirstAvailable 将输入通道中的元素发送到第一个可以接收的输出通道中，每个元素只会发送到一个输出通道。
+// 空闲的消费者会获得更多元素，适用于处理耗时不均的场景。
+// 输入通道关闭或上下文取消时，所有输出通道被关闭。
+func DistributeFirstAvailable[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+	if n <= 0 {
+		n = 1
+	}
+	outs := make([]chan T, n)
+	results := make([]<-chan T, n)
+	cases := make([]reflect.SelectCase, n+1)
+	cases[n] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	for i := range outs {
+		outs[i] = make(chan T)
+		results[i] = outs[i]
+		cases[i] = reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.ValueOf(outs[i])}
+	}
+	go func() {
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case value, ok := <-in:
+				if !ok {
+					return
+				}
+				v := reflect.ValueOf(&value).Elem()
+				for i := 0; i < n; i++ {
+					cases[i].Send = v
+				}
+				if chosen, _, _ := reflect.Select(cases); chosen == n {
+					return
+				}
+			}
+		}
+	}()
+	return results
+}
+
+// distribute 按照 index 返回的下标将输入通道中的元素分发到 n 个输出通道中。
+func distribute[T any](ctx context.Context, in <-chan T, n int, index func(T) int) []<-chan T {
+	outs := make([]chan T, n)
+	results := make([]<-chan T, n)
+	for i := range outs {
+		outs[i] = make(chan T)
+		results[i] = outs[i]
+	}
+	go func() {
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case value, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case outs[index(value)] <- value:
+				}
+			}
+		}
+	}()
+	return results
+}
@@ -0,0 +1,72 @@
+package chanx
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// collectAll 并发收集多个通道中的所有值
+func collectAll[T any](outs []<-chan T) [][]T {
+	var wg sync.WaitGroup
+	results := make([][]T, len(outs))
+	for i, out := range outs {
+		wg.Add(1)
+		go func(i int, out <-chan T) {
+			defer wg.Done()
+			results[i] = collect(out)
+		}(i, out)
+	}
+	wg.Wait()
+	return results
+}
+
+func TestDistributeRoundRobin(t *testing.T) {
+	ctx := context.Background()
+	results := collectAll(DistributeRoundRobin(ctx, Emit(ctx, rangeValues(6)...), 3))
+	want := [][]int{{0, 3}, {1, 4}, {2, 5}}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("expected %v, got %v", want, results)
+	}
+}
+
+func TestDistributeByKey(t *testing.T) {
+	ctx := context.Background()
+	values := rangeValues(100)
+	results := collectAll(DistributeByKey(ctx, Emit(ctx, values...), 4, func(v int) string {
+		return strconv.Itoa(v % 10)
+	}))
+	owner := make(map[int]int)
+	total := 0
+	for i, result := range results {
+		total += len(result)
+		if !sort.IntsAreSorted(result) {
+			t.Errorf("output %d not ordered: %v", i, result)
+		}
+		for _, v := range result {
+			if o, ok := owner[v%10]; ok && o != i {
+				t.Errorf("key %d delivered to outputs %d and %d", v%10, o, i)
+			}
+			owner[v%10] = i
+		}
+	}
+	if total != len(values) {
+		t.Errorf("expected %d values, got %d", len(values), total)
+	}
+}
+
+func TestDistributeFirstAvailable(t *testing.T) {
+	ctx := context.Background()
+	results := collectAll(DistributeFirstAvailable(ctx, Emit(ctx, rangeValues(50)...), 3))
+	var all []int
+	for _, result := range results {
+		all = append(all, result...)
+	}
+	sort.Ints(all)
+	if !reflect.DeepEqual(all, rangeValues(50)) {
+		t.Errorf("unexpected values: %v", all)
+	}
+}
@@ -0,0 +1,231 @@
+package chanx
+
+import (
+	"sync/atomic"
+)
+
+// OverflowPolicy 定义 Elastic 达到最大容量时的处理策略。
+type OverflowPolicy int
+
+const (
+	// BlockOnFull 缓冲区满时阻塞生产者，直到消费者取走元素。
+	BlockOnFull OverflowPolicy = iota
+	// DropOldest 缓冲区满时丢弃最早的元素，接收新元素。
+	DropOldest
+	// DropNewest 缓冲区满时丢弃新元素。
+	DropNewest
+)
+
+type elasticOptions struct {
+	InitialSize int
+
+	MaxSize int
+
+	Policy OverflowPolicy
+
+	HighWatermark int
+
+	OnHighWatermark func(length int)
+}
+
+// ElasticOption 用于配置 Elastic
+type ElasticOption func(*elasticOptions)
+
+// InitialSize 设置缓冲区的初始容量。
+func InitialSize(size int) ElasticOption {
+	return func(o *elasticOptions) {
+		o.InitialSize = size
+	}
+}
+
+// MaxSize 设置缓冲区的最大容量以及达到最大容量时的处理策略，size 小于等于 0 表示不限制。
+func MaxSize(size int, policy OverflowPolicy) ElasticOption {
+	return func(o *elasticOptions) {
+		o.MaxSize = size
+		o.Policy = policy
+	}
+}
+
+// HighWatermark 设置高水位回调，缓冲区长度达到 mark 时调用 f。
+// 每次长度从低于 mark 增长到 mark 时调用一次，之后直到消费者取走元素使长度低于 mark 前都不会再次调用，
+// 即使 DropOldest 策略在长度保持为 mark 时持续丢弃元素。f 在 Elastic 的内部 goroutine 中执行，不应阻塞。
+func HighWatermark(mark int, f func(length int)) ElasticOption {
+	return func(o *elasticOptions) {
+		o.HighWatermark = mark
+		o.OnHighWatermark = f
+	}
+}
+
+func (o *elasticOptions) Apply(opts ...ElasticOption) *elasticOptions {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func (o *elasticOptions) Correct() *elasticOptions {
+	if o.InitialSize <= 0 {
+		o.InitialSize = 16
+	}
+	if o.MaxSize > 0 && o.InitialSize > o.MaxSize {
+		o.InitialSize = o.MaxSize
+	}
+	if o.OnHighWatermark == nil || o.HighWatermark <= 0 {
+		o.OnHighWatermark = nil
+	}
+	return o
+}
+
+// Elastic 是一个无界（弹性）通道，生产者向 In 发送的值被缓存在可增长的环形缓冲区中，
+// 消费者从 Out 接收。关闭 In 后，缓冲区中剩余的值会继续从 Out 输出，全部输出后 Out 被关闭。
+type Elastic[T any] struct {
+	options *elasticOptions
+	in      chan T
+	out     chan T
+	buf     *ring[T]
+	length  atomic.Int64
+	dropped atomic.Uint64
+	// aboveMark 长度达到高水位后为 true，消费者取走元素使长度低于高水位时重置
+	aboveMark bool
+}
+
+// NewElastic 创建一个 Elastic 通道。
+func NewElastic[T any](opts ...ElasticOption) *Elastic[T] {
+	o := new(elasticOptions).Apply(opts...).Correct()
+	e := &Elastic[T]{
+		options: o,
+		in:      make(chan T),
+		out:     make(chan T),
+		buf:     newRing[T](o.InitialSize),
+	}
+	go e.loop()
+	return e
+}
+
+// In 返回用于发送值的通道，关闭该通道表示不再有新值。
+func (e *Elastic[T]) In() chan<- T {
+	return e.in
+}
+
+// Out 返回用于接收值的通道。
+func (e *Elastic[T]) Out() <-chan T {
+	return e.out
+}
+
+// Len 返回缓冲区中尚未被接收的值的数量。
+func (e *Elastic[T]) Len() int {
+	return int(e.length.Load())
+}
+
+// Dropped 返回因超过最大容量而被丢弃的值的数量。
+func (e *Elastic[T]) Dropped() uint64 {
+	return e.dropped.Load()
+}
+
+func (e *Elastic[T]) loop() {
+	defer close(e.out)
+	in := e.in
+	for {
+		var inC <-chan T
+		if in != nil && !(e.full() && e.options.Policy == BlockOnFull) {
+			inC = in
+		}
+		var outC chan<- T
+		var next T
+		if e.buf.Len() > 0 {
+			outC = e.out
+			next = e.buf.Peek()
+		}
+		if inC == nil && outC == nil {
+			// In 已关闭且缓冲区已排空
+			return
+		}
+		select {
+		case v, ok := <-inC:
+			if !ok {
+				in = nil
+				continue
+			}
+			e.push(v)
+		case outC <- next:
+			e.buf.Pop()
+			length := e.buf.Len()
+			e.length.Store(int64(length))
+			if length < e.options.HighWatermark {
+				e.aboveMark = false
+			}
+		}
+	}
+}
+
+func (e *Elastic[T]) full() bool {
+	return e.options.MaxSize > 0 && e.buf.Len() >= e.options.MaxSize
+}
+
+func (e *Elastic[T]) push(v T) {
+	if e.full() {
+		switch e.options.Policy {
+		case DropNewest:
+			e.dropped.Add(1)
+			return
+		case DropOldest:
+			e.buf.Pop()
+			e.dropped.Add(1)
+		}
+	}
+	e.buf.Push(v)
+	length := e.buf.Len()
+	e.length.Store(int64(length))
+	if e.options.OnHighWatermark != nil && !e.aboveMark && length >= e.options.HighWatermark {
+		e.aboveMark = true
+		e.options.OnHighWatermark(length)
+	}
+}
+
+// ring 是一个可增长的环形缓冲区，非并发安全。
+type ring[T any] struct {
+	buf  []T
+	head int
+	size int
+}
+
+func newRing[T any](size int) *ring[T] {
+	return &ring[T]{buf: make([]T, size)}
+}
+
+func (r *ring[T]) Len() int {
+	return r.size
+}
+
+func (r *ring[T]) Push(v T) {
+	if r.size == len(r.buf) {
+		r.grow()
+	}
+	r.buf[(r.head+r.size)%len(r.buf)] = v
+	r.size++
+}
+
+func (r *ring[T]) Peek() T {
+	return r.buf[r.head]
+}
+
+func (r *ring[T]) Pop() T {
+	var zero T
+	v := r.buf[r.head]
+	r.buf[r.head] = zero
+	r.head = (r.head + 1) % len(r.buf)
+	r.size--
+	return v
+}
+
+func (r *ring[T]) grow() {
+	size := len(r.buf) * 2
+	if size == 0 {
+		size = 1
+	}
+	buf := make([]T, size)
+	n := copy(buf, r.buf[r.head:])
+	copy(buf[n:], r.buf[:r.head])
+	r.buf = buf
+	r.head = 0
+}
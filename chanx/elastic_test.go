@@ -0,0 +1,122 @@
+package chanx
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestElasticDrainOnClose(t *testing.T) {
+	e := NewElastic[int](InitialSize(2))
+	for i := 0; i < 100; i++ {
+		e.In() <- i
+	}
+	close(e.In())
+	i := 0
+	for v := range e.Out() {
+		if v != i {
+			t.Fatalf("expected %d, got %d", i, v)
+		}
+		i++
+	}
+	if i != 100 {
+		t.Errorf("expected 100 values, got %d", i)
+	}
+}
+
+func TestElasticLenAndHighWatermark(t *testing.T) {
+	marks := make(chan int, 1)
+	e := NewElastic[int](HighWatermark(5, func(length int) { marks <- length }))
+	for i := 0; i < 5; i++ {
+		e.In() <- i
+	}
+	select {
+	case n := <-marks:
+		if n != 5 {
+			t.Errorf("expected high watermark at 5, got %d", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("high watermark callback not called")
+	}
+	if n := e.Len(); n != 5 {
+		t.Errorf("expected len 5, got %d", n)
+	}
+	close(e.In())
+	Discard(e.Out())
+	if n := e.Len(); n != 0 {
+		t.Errorf("expected len 0, got %d", n)
+	}
+}
+
+func TestElasticDropOldest(t *testing.T) {
+	e := NewElastic[int](MaxSize(3, DropOldest))
+	for i := 0; i < 10; i++ {
+		e.In() <- i
+	}
+	close(e.In())
+	var values []int
+	for v := range e.Out() {
+		values = append(values, v)
+	}
+	if len(values) != 3 || values[0] != 7 || values[2] != 9 {
+		t.Errorf("unexpected values: %v", values)
+	}
+	if d := e.Dropped(); d != 7 {
+		t.Errorf("expected 7 dropped, got %d", d)
+	}
+}
+
+func TestElasticHighWatermarkDropOldest(t *testing.T) {
+	var marks atomic.Int32
+	e := NewElastic[int](MaxSize(3, DropOldest), HighWatermark(3, func(length int) { marks.Add(1) }))
+	for i := 0; i < 10; i++ {
+		e.In() <- i
+	}
+	// 接收一个值，保证之前的值都已经进入缓冲区
+	<-e.Out()
+	if n := marks.Load(); n != 1 {
+		t.Errorf("expected 1 high watermark callback during overflow, got %d", n)
+	}
+	e.In() <- 10
+	<-e.Out()
+	if n := marks.Load(); n != 2 {
+		t.Errorf("expected callback after crossing the mark again, got %d", n)
+	}
+	close(e.In())
+	Discard(e.Out())
+}
+
+func TestElasticDropNewest(t *testing.T) {
+	e := NewElastic[int](MaxSize(3, DropNewest))
+	for i := 0; i < 10; i++ {
+		e.In() <- i
+	}
+	close(e.In())
+	var values []int
+	for v := range e.Out() {
+		values = append(values, v)
+	}
+	if len(values) != 3 || values[0] != 0 || values[2] != 2 {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
+
+func TestElasticBlockOnFull(t *testing.T) {
+	e := NewElastic[int](MaxSize(2, BlockOnFull))
+	e.In() <- 1
+	e.In() <- 2
+	select {
+	case e.In() <- 3:
+		t.Fatal("expected producer to block")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if v := <-e.Out(); v != 1 {
+		t.Errorf("expected 1, got %d", v)
+	}
+	select {
+	case e.In() <- 3:
+	case <-time.After(time.Second):
+		t.Fatal("expected producer to be unblocked")
+	}
+	close(e.In())
+}
@@ -0,0 +1,197 @@
+package chanx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/soyacen/goconc/brave"
+)
+
+// Graph 用于声明和运行由源、转换和汇组成的管道，统一管理各阶段的上下文、协程和中间通道的关闭。
+// 任意阶段返回错误或发生 panic 时，整个管道被取消，Wait 返回第一个错误。
+//
+//	g := chanx.NewGraph(ctx)
+//	nums := chanx.Source(g, "nums", produce)
+//	squares := chanx.Transform(g, "square", nums, 4, square)
+//	chanx.Sink(g, "print", squares, print)
+//	g.Run()
+//	err := g.Wait()
+type Graph struct {
+	// ctx 是所有阶段共享的上下文，出现错误时被取消
+	ctx    context.Context
+	cancel context.CancelFunc
+	// sourceCtx 是源阶段的上下文，Stop 时被取消，下游阶段处理完剩余元素后自然退出
+	sourceCtx context.Context
+	stop      context.CancelFunc
+
+	mu      sync.Mutex
+	wg      sync.WaitGroup
+	stages  []func()
+	started bool
+	stopped bool
+	err     error
+}
+
+// Stream 是管道中两个阶段之间的数据流，只能被一个下游阶段消费。
+type Stream[T any] struct {
+	name string
+	c    chan T
+}
+
+// Name 返回产生该数据流的阶段名称。
+func (s *Stream[T]) Name() string {
+	return s.name
+}
+
+// NewGraph 创建一个在 ctx 下运行的 Graph，ctx 取消时整个管道被取消。
+func NewGraph(ctx context.Context) *Graph {
+	g := &Graph{}
+	g.ctx, g.cancel = context.WithCancel(ctx)
+	g.sourceCtx, g.stop = context.WithCancel(g.ctx)
+	return g
+}
+
+// Source 声明一个源阶段，f 通过 emit 向下游发送元素，f 返回后输出的数据流被关闭。
+// 传给 f 的上下文在 Stop 时被取消，f 应当在上下文取消时尽快返回。
+// emit 在管道被取消时返回上下文的错误。
+func Source[T any](g *Graph, name string, f func(ctx context.Context, emit func(T) error) error) *Stream[T] {
+	out := &Stream[T]{name: name, c: make(chan T)}
+	emit := func(value T) error {
+		select {
+		case <-g.ctx.Done():
+			return g.ctx.Err()
+		case out.c <- value:
+			return nil
+		}
+	}
+	g.add(name, func() error {
+		defer close(out.c)
+		err := f(g.sourceCtx, emit)
+		if err != nil && g.isStopped() && errors.Is(err, context.Canceled) {
+			// Stop 导致的取消不是错误
+			return nil
+		}
+		return err
+	})
+	return out
+}
+
+// Transform 声明一个转换阶段，使用 concurrency 个协程并发地对 in 中的元素调用 f，并将结果发送到输出的数据流。
+// concurrency 大于 1 时不保证输出顺序。f 返回错误时整个管道被取消。
+func Transform[T any, R any](g *Graph, name string, in *Stream[T], concurrency int, f func(ctx context.Context, value T) (R, error)) *Stream[R] {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	out := &Stream[R]{name: name, c: make(chan R)}
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		g.add(name, func() error {
+			defer wg.Done()
+			for value := range in.c {
+				result, err := f(g.ctx, value)
+				if err != nil {
+					return err
+				}
+				select {
+				case <-g.ctx.Done():
+					return nil
+				case out.c <- result:
+				}
+			}
+			return nil
+		})
+	}
+	g.add(name, func() error {
+		wg.Wait()
+		close(out.c)
+		return nil
+	})
+	return out
+}
+
+// Sink 声明一个汇阶段，对 in 中的每个元素调用 f。f 返回错误时整个管道被取消。
+func Sink[T any](g *Graph, name string, in *Stream[T], f func(ctx context.Context, value T) error) {
+	g.add(name, func() error {
+		for value := range in.c {
+			if err := f(g.ctx, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Run 启动所有已声明的阶段，重复调用不做任何事。Run 之后声明的阶段会立即启动。
+func (g *Graph) Run() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.started {
+		return
+	}
+	g.started = true
+	for _, stage := range g.stages {
+		g.wg.Add(1)
+		go stage()
+	}
+	g.stages = nil
+}
+
+// Stop 取消所有源阶段，已经发出的元素会继续被下游处理，所有阶段处理完后 Wait 返回。
+func (g *Graph) Stop() {
+	g.mu.Lock()
+	g.stopped = true
+	g.mu.Unlock()
+	g.stop()
+}
+
+// Wait 等待所有阶段退出，返回第一个出错阶段的错误，需要在 Run 之后调用。
+// 没有阶段出错但 NewGraph 的上下文被取消时，返回该上下文的错误。
+func (g *Graph) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	err := g.err
+	g.mu.Unlock()
+	if err == nil && !g.isStopped() {
+		err = g.ctx.Err()
+	}
+	g.cancel()
+	return err
+}
+
+// add 添加一个阶段，阶段中的 panic 通过 brave 被转换为错误。
+func (g *Graph) add(name string, stage func() error) {
+	run := func() {
+		defer g.wg.Done()
+		err := brave.DoE(stage)
+		if err != nil {
+			g.fail(fmt.Errorf("chanx: stage %s: %w", name, err))
+		}
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.started {
+		g.wg.Add(1)
+		go run()
+		return
+	}
+	g.stages = append(g.stages, run)
+}
+
+// fail 记录第一个错误并取消整个管道。
+func (g *Graph) fail(err error) {
+	g.mu.Lock()
+	if g.err == nil {
+		g.err = err
+	}
+	g.mu.Unlock()
+	g.cancel()
+}
+
+func (g *Graph) isStopped() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.stopped
+}
@@ -0,0 +1,125 @@
+package chanx
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGraph(t *testing.T) {
+	g := NewGraph(context.Background())
+	nums := Source(g, "nums", func(ctx context.Context, emit func(int) error) error {
+		for i := 1; i <= 10; i++ {
+			if err := emit(i); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	squares := Transform(g, "square", nums, 3, func(ctx context.Context, v int) (int, error) {
+		return v * v, nil
+	})
+	var mu sync.Mutex
+	var got []int
+	Sink(g, "collect", squares, func(ctx context.Context, v int) error {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, v)
+		return nil
+	})
+	g.Run()
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	sort.Ints(got)
+	if len(got) != 10 || got[0] != 1 || got[9] != 100 {
+		t.Errorf("unexpected result %v", got)
+	}
+}
+
+func TestGraphError(t *testing.T) {
+	g := NewGraph(context.Background())
+	nums := Source(g, "nums", func(ctx context.Context, emit func(int) error) error {
+		for i := 0; ; i++ {
+			if err := emit(i); err != nil {
+				return err
+			}
+		}
+	})
+	errBoom := errors.New("boom")
+	doubled := Transform(g, "double", nums, 2, func(ctx context.Context, v int) (int, error) {
+		if v == 5 {
+			return 0, errBoom
+		}
+		return v * 2, nil
+	})
+	Sink(g, "discard", doubled, func(ctx context.Context, v int) error { return nil })
+	g.Run()
+	err := g.Wait()
+	if !errors.Is(err, errBoom) || !strings.Contains(err.Error(), "double") {
+		t.Errorf("expected stage error, got %v", err)
+	}
+}
+
+func TestGraphPanic(t *testing.T) {
+	g := NewGraph(context.Background())
+	nums := Source(g, "nums", func(ctx context.Context, emit func(int) error) error {
+		return emit(1)
+	})
+	Sink(g, "panic", nums, func(ctx context.Context, v int) error {
+		panic("oops")
+	})
+	g.Run()
+	if err := g.Wait(); err == nil || !strings.Contains(err.Error(), "oops") {
+		t.Errorf("expected panic error, got %v", err)
+	}
+}
+
+func TestGraphStop(t *testing.T) {
+	g := NewGraph(context.Background())
+	nums := Source(g, "nums", func(ctx context.Context, emit func(int) error) error {
+		for i := 0; ; i++ {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+			if err := emit(i); err != nil {
+				return err
+			}
+		}
+	})
+	var count int
+	Sink(g, "count", nums, func(ctx context.Context, v int) error {
+		count++
+		if count == 5 {
+			g.Stop()
+		}
+		return nil
+	})
+	g.Run()
+	if err := g.Wait(); err != nil {
+		t.Fatalf("expected graceful stop, got %v", err)
+	}
+	if count < 5 {
+		t.Errorf("expected at least 5 values, got %d", count)
+	}
+}
+
+func TestGraphCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := NewGraph(ctx)
+	nums := Source(g, "nums", func(ctx context.Context, emit func(int) error) error {
+		<-ctx.Done()
+		return nil
+	})
+	Sink(g, "discard", nums, func(ctx context.Context, v int) error { return nil })
+	g.Run()
+	cancel()
+	if err := g.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package chanx
+
+import (
+	"context"
+	"time"
+)
+
+// GroupedStream 是 GroupBy 输出的一个分组，C 按输入顺序输出该分组的所有元素。
+type GroupedStream[K comparable, T any] struct {
+	Key K
+	C   <-chan T
+}
+
+type groupByOptions struct {
+	IdleTimeout time.Duration
+
+	MaxGroups int
+
+	Buffer int
+}
+
+// GroupByOption 用于配置 GroupBy
+type GroupByOption func(*groupByOptions)
+
+// IdleTimeout 设置分组的空闲超时时间，分组超过 d 没有收到新元素时关闭其通道。
+// 之后再收到相同 key 的元素时会创建新的分组。d 小于等于 0 表示不过期。
+func IdleTimeout(d time.Duration) GroupByOption {
+	return func(o *groupByOptions) {
+		o.IdleTimeout = d
+	}
+}
+
+// MaxGroups 设置同时存在的分组的最大数量，达到上限时关闭最久没有收到元素的分组，为新分组腾出位置。
+// n 小于等于 0 表示不限制。
+func MaxGroups(n int) GroupByOption {
+	return func(o *groupByOptions) {
+		o.MaxGroups = n
+	}
+}
+
+// GroupBuffer 设置每个分组通道的缓冲区大小。
+func GroupBuffer(size int) GroupByOption {
+	return func(o *groupByOptions) {
+		o.Buffer = size
+	}
+}
+
+func (o *groupByOptions) Apply(opts ...GroupByOption) *groupByOptions {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func (o *groupByOptions) Correct() *groupByOptions {
+	if o.Buffer < 0 {
+		o.Buffer = 0
+	}
+	return o
+}
+
+// GroupBy 按照 key 将输入通道中的元素分组，每出现一个新的 key 时输出一个 GroupedStream。
+// 分组由单个 goroutine 分发，任何一个分组的消费者过慢都会阻塞所有分组，可以通过 GroupBuffer 设置缓冲区。
+// 输入通道关闭或上下文取消时，所有分组通道和输出通道被关闭。
+func GroupBy[K comparable, T any](ctx context.Context, in <-chan T, key func(T) K, opts ...GroupByOption) <-chan GroupedStream[K, T] {
+	var out chan GroupedStream[K, T]
+	if in == nil {
+		return out
+	}
+	o := new(groupByOptions).Apply(opts...).Correct()
+	out = make(chan GroupedStream[K, T])
+	go func() {
+		type group struct {
+			c    chan T
+			last time.Time
+		}
+		groups := make(map[K]*group)
+		closeGroup := func(k K) {
+			close(groups[k].c)
+			delete(groups, k)
+		}
+		defer func() {
+			for k := range groups {
+				closeGroup(k)
+			}
+			close(out)
+		}()
+
+		clock := ClockFrom(ctx)
+		var tickC <-chan time.Time
+		if o.IdleTimeout > 0 {
+			// 每半个超时时间检查一次空闲分组，至少 1ns，防止 NewTicker 因非正数间隔 panic
+			ticker := clock.NewTicker(max(o.IdleTimeout/2, 1))
+			defer ticker.Stop()
+			tickC = ticker.C()
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-tickC:
+				for k, g := range groups {
+					if now.Sub(g.last) >= o.IdleTimeout {
+						closeGroup(k)
+					}
+				}
+			case value, ok := <-in:
+				if !ok {
+					return
+				}
+				k := key(value)
+				g, exists := groups[k]
+				if !exists {
+					if o.MaxGroups > 0 && len(groups) >= o.MaxGroups {
+						// 关闭最久没有收到元素的分组
+						var oldest K
+						var oldestTime time.Time
+						first := true
+						for gk, gg := range groups {
+							if first || gg.last.Before(oldestTime) {
+								oldest, oldestTime, first = gk, gg.last, false
+							}
+						}
+						closeGroup(oldest)
+					}
+					g = &group{c: make(chan T, o.Buffer)}
+					groups[k] = g
+					select {
+					case <-ctx.Done():
+						return
+					case out <- GroupedStream[K, T]{Key: k, C: g.c}:
+					}
+				}
+				g.last = clock.Now()
+				select {
+				case <-ctx.Done():
+					return
+				case g.c <- value:
+				}
+			}
+		}
+	}()
+	return out
+}
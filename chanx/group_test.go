@@ -0,0 +1,93 @@
+package chanx
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGroupBy(t *testing.T) {
+	ctx := context.Background()
+	groups := GroupBy(ctx, Emit(ctx, rangeValues(10)...), func(v int) int { return v % 3 })
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	results := make(map[int][]int)
+	for g := range groups {
+		wg.Add(1)
+		go func(g GroupedStream[int, int]) {
+			defer wg.Done()
+			values := collect(g.C)
+			mu.Lock()
+			results[g.Key] = values
+			mu.Unlock()
+		}(g)
+	}
+	wg.Wait()
+	want := map[int][]int{0: {0, 3, 6, 9}, 1: {1, 4, 7}, 2: {2, 5, 8}}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("expected %v, got %v", want, results)
+	}
+}
+
+func TestGroupByIdleTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in := make(chan string)
+	groups := GroupBy(ctx, in, func(v string) string { return v }, IdleTimeout(20*time.Millisecond), GroupBuffer(1))
+	in <- "a"
+	g := <-groups
+	if v := <-g.C; v != "a" {
+		t.Errorf("expected a, got %s", v)
+	}
+	select {
+	case _, ok := <-g.C:
+		if ok {
+			t.Error("expected closed group")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("idle group not closed")
+	}
+	in <- "a"
+	g = <-groups
+	if v := <-g.C; v != "a" {
+		t.Errorf("expected a, got %s", v)
+	}
+}
+
+func TestGroupByTinyIdleTimeout(t *testing.T) {
+	ctx := context.Background()
+	groups := GroupBy(ctx, Emit(ctx, 1, 2, 3), func(v int) int { return v }, IdleTimeout(1))
+	var wg sync.WaitGroup
+	for g := range groups {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range g.C {
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestGroupByMaxGroups(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in := make(chan int)
+	groups := GroupBy(ctx, in, func(v int) int { return v }, MaxGroups(1), GroupBuffer(1))
+	in <- 1
+	first := <-groups
+	in <- 2
+	second := <-groups
+	if second.Key != 2 {
+		t.Errorf("expected key 2, got %d", second.Key)
+	}
+	if got := collect(first.C); !reflect.DeepEqual(got, []int{1}) {
+		t.Errorf("unexpected values in evicted group: %v", got)
+	}
+	close(in)
+	if got := collect(second.C); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("unexpected values: %v", got)
+	}
+}
@@ -0,0 +1,161 @@
+package chanx
+
+import (
+	"context"
+	"iter"
+)
+
+// Seq 将通道转换为 iter.Seq，在通道关闭、上下文取消或循环提前结束时停止迭代。
+// Seq 无法停止生产者：循环提前结束后，向 in 发送值的生产者可能永远阻塞。
+// 通常应使用 SeqFunc，它在循环提前结束时会取消生产者；Seq 只适用于由调用者自己管理生产者生命周期的通道。
+func Seq[T any](ctx context.Context, in <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case value, ok := <-in:
+				if !ok || !yield(value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Seq2 将通道转换为 iter.Seq2，依次产生元素的序号和值，停止条件与 Seq 相同。
+// 与 Seq 一样无法停止生产者，通常应使用 Seq2Func。
+func Seq2[T any](ctx context.Context, in <-chan T) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for value := range Seq(ctx, in) {
+			if !yield(i, value) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// SeqE 将 Result 通道转换为 iter.Seq2，依次产生每个 Result 的值和错误，停止条件与 Seq 相同。
+func SeqE[T any](ctx context.Context, in <-chan Result[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for r := range Seq(ctx, in) {
+			if !yield(r.Value, r.Err) {
+				return
+			}
+		}
+	}
+}
+
+// SeqFunc 在每次迭代开始时调用 source 创建生产者通道，并将其转换为 iter.Seq。
+// 迭代结束时（包括循环提前结束）取消传给 source 的上下文以停止生产者，并丢弃通道中剩余的值，防止生产者阻塞。
+func SeqFunc[T any](ctx context.Context, source func(ctx context.Context) <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		in := source(ctx)
+		defer AsyncDiscard(in)
+		for value := range Seq(ctx, in) {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
+
+// Seq2Func 是 SeqFunc 的 iter.Seq2 版本，依次产生元素的序号和值。
+// 迭代结束时（包括循环提前结束）取消传给 source 的上下文以停止生产者。
+func Seq2Func[T any](ctx context.Context, source func(ctx context.Context) <-chan T) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for value := range SeqFunc(ctx, source) {
+			if !yield(i, value) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// FromSeq 将 iter.Seq 转换为通道，在新的 goroutine 中迭代 seq 并发送到输出通道。
+// 迭代完成或上下文取消时，输出通道被关闭。
+func FromSeq[T any](ctx context.Context, seq iter.Seq[T]) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for value := range seq {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- value:
+			}
+		}
+	}()
+	return out
+}
+
+// FromSeqE 将产生值和错误的 iter.Seq2 转换为 Result 通道，迭代完成或上下文取消时，输出通道被关闭。
+func FromSeqE[T any](ctx context.Context, seq iter.Seq2[T, error]) <-chan Result[T] {
+	out := make(chan Result[T])
+	go func() {
+		defer close(out)
+		for value, err := range seq {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- Result[T]{Value: value, Err: err}:
+			}
+		}
+	}()
+	return out
+}
+
+// ReduceSeq 是 Reduce 的 iter.Seq 版本，惰性地产生每一次归约的中间结果，不会创建 goroutine。
+func ReduceSeq[T any, R any](seq iter.Seq[T], identity R, accumulator func(R, T) R) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		acc := identity
+		for value := range seq {
+			acc = accumulator(acc, value)
+			if !yield(acc) {
+				return
+			}
+		}
+	}
+}
+
+// MaxSeq 是 Max 的 iter.Seq 版本，返回 seq 中的最大值，seq 为空时第二个返回值为 false。
+func MaxSeq[T any](seq iter.Seq[T], cmp func(a, b T) int) (T, bool) {
+	var maxValue T
+	found := false
+	for value := range seq {
+		if !found || cmp(value, maxValue) > 0 {
+			maxValue = value
+			found = true
+		}
+	}
+	return maxValue, found
+}
+
+// MinSeq 是 Min 的 iter.Seq 版本，返回 seq 中的最小值，seq 为空时第二个返回值为 false。
+func MinSeq[T any](seq iter.Seq[T], cmp func(a, b T) int) (T, bool) {
+	var minValue T
+	found := false
+	for value := range seq {
+		if !found || cmp(value, minValue) < 0 {
+			minValue = value
+			found = true
+		}
+	}
+	return minValue, found
+}
+
+// AllMatchSeq 是 AllMatch 的 iter.Seq 版本，检查 seq 中所有元素是否满足给定条件，遇到不满足的元素立即停止迭代。
+func AllMatchSeq[T any](seq iter.Seq[T], predicate func(value T) bool) bool {
+	for value := range seq {
+		if !predicate(value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package chanx
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSeq(t *testing.T) {
+	ctx := context.Background()
+	var got []int
+	for v := range Seq(ctx, Emit(ctx, 1, 2, 3)) {
+		got = append(got, v)
+	}
+	if !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("unexpected values: %v", got)
+	}
+
+	for i, v := range Seq2(ctx, Emit(ctx, "a", "b")) {
+		if (i == 0 && v != "a") || (i == 1 && v != "b") {
+			t.Errorf("unexpected pair %d, %s", i, v)
+		}
+	}
+}
+
+// counter 返回一个持续产生递增整数的生产者，生产者退出时关闭 stopped
+func counter(stopped chan struct{}) func(ctx context.Context) <-chan int {
+	return func(ctx context.Context) <-chan int {
+		out := make(chan int)
+		go func() {
+			defer close(stopped)
+			defer close(out)
+			for i := 0; ; i++ {
+				select {
+				case <-ctx.Done():
+					return
+				case out <- i:
+				}
+			}
+		}()
+		return out
+	}
+}
+
+func TestSeqFuncCancelsProducer(t *testing.T) {
+	stopped := make(chan struct{})
+	seq := SeqFunc(context.Background(), counter(stopped))
+	var got []int
+	for v := range seq {
+		if v == 3 {
+			break
+		}
+		got = append(got, v)
+	}
+	if !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Errorf("unexpected values: %v", got)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("producer not stopped after break")
+	}
+}
+
+func TestSeq2FuncCancelsProducer(t *testing.T) {
+	stopped := make(chan struct{})
+	for i, v := range Seq2Func(context.Background(), counter(stopped)) {
+		if i != v {
+			t.Errorf("unexpected pair %d, %d", i, v)
+		}
+		if i == 3 {
+			break
+		}
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("producer not stopped after break")
+	}
+}
+
+func TestFromSeq(t *testing.T) {
+	ctx := context.Background()
+	got := collect(FromSeq(ctx, slices.Values([]int{1, 2, 3})))
+	if !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("unexpected values: %v", got)
+	}
+
+	errBoom := errors.New("boom")
+	seq := func(yield func(int, error) bool) {
+		_ = yield(1, nil) && yield(0, errBoom)
+	}
+	var errs []error
+	for v, err := range SeqE(ctx, FromSeqE(ctx, seq)) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if v != 1 {
+			t.Errorf("unexpected value %d", v)
+		}
+	}
+	if len(errs) != 1 || !errors.Is(errs[0], errBoom) {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestSeqAggregations(t *testing.T) {
+	seq := slices.Values([]int{3, 1, 4, 1, 5})
+	cmp := func(a, b int) int { return a - b }
+	if v, ok := MaxSeq(seq, cmp); !ok || v != 5 {
+		t.Errorf("expected max 5, got %d, %v", v, ok)
+	}
+	if v, ok := MinSeq(seq, cmp); !ok || v != 1 {
+		t.Errorf("expected min 1, got %d, %v", v, ok)
+	}
+	if _, ok := MaxSeq(slices.Values([]int(nil)), cmp); ok {
+		t.Error("expected no max for empty sequence")
+	}
+	if !AllMatchSeq(seq, func(v int) bool { return v > 0 }) {
+		t.Error("expected all positive")
+	}
+	if AllMatchSeq(seq, func(v int) bool { return v > 1 }) {
+		t.Error("expected not all greater than 1")
+	}
+	got := slices.Collect(ReduceSeq(seq, 0, func(acc, v int) int { return acc + v }))
+	if !reflect.DeepEqual(got, []int{3, 4, 8, 9, 14}) {
+		t.Errorf("unexpected values: %v", got)
+	}
+}
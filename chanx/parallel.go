@@ -0,0 +1,197 @@
+package chanx
+
+import (
+	"context"
+	"sync"
+
+	"github.com/soyacen/goconc/gofer"
+)
+
+type parallelOptions struct {
+	Ordered bool
+
+	Window int
+
+	Gofer gofer.Gofer
+}
+
+// ParallelOption 用于配置 ParallelMap
+type ParallelOption func(*parallelOptions)
+
+// Ordered 使 ParallelMap 按输入顺序输出结果，window 为重排缓冲区的大小，
+// 即已开始处理但尚未输出的元素的最大数量。
+func Ordered(window int) ParallelOption {
+	return func(o *parallelOptions) {
+		o.Ordered = true
+		o.Window = window
+	}
+}
+
+// OnGofer 使 ParallelMap 在 g 上执行 mapper，而不是创建新的 goroutine。
+// 如果 g 拒绝了任务，mapper 将在调度 goroutine 中直接执行；如果 g 接受任务后又丢弃了任务，mapper 将在新的 goroutine 中执行。
+func OnGofer(g gofer.Gofer) ParallelOption {
+	return func(o *parallelOptions) {
+		o.Gofer = g
+	}
+}
+
+func (o *parallelOptions) Apply(opts ...ParallelOption) *parallelOptions {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func (o *parallelOptions) Correct(workers int) *parallelOptions {
+	if o.Window < workers {
+		o.Window = workers
+	}
+	return o
+}
+
+// ParallelMap 使用最多 workers 个并发任务对输入通道中的元素执行 mapper，并通过输出通道返回结果。
+// 默认情况下结果按完成顺序输出，使用 Ordered 选项可按输入顺序输出。
+// 当输入通道关闭且所有任务完成，或上下文取消时，输出通道被关闭。
+func ParallelMap[T any, R any](ctx context.Context, in <-chan T, workers int, mapper func(T) R, opts ...ParallelOption) <-chan R {
+	var out chan R
+	if in == nil {
+		return out
+	}
+	if workers <= 0 {
+		workers = 1
+	}
+	o := new(parallelOptions).Apply(opts...).Correct(workers)
+	out = make(chan R, workers)
+	if o.Ordered {
+		go orderedParallelMap(ctx, in, out, workers, mapper, o)
+	} else {
+		go unorderedParallelMap(ctx, in, out, workers, mapper, o)
+	}
+	return out
+}
+
+// unorderedParallelMap 任务完成后直接将结果发送到 out。
+func unorderedParallelMap[T any, R any](ctx context.Context, in <-chan T, out chan R, workers int, mapper func(T) R, o *parallelOptions) {
+	var wg sync.WaitGroup
+	defer func() {
+		wg.Wait()
+		close(out)
+	}()
+	sem := make(chan struct{}, workers)
+	for {
+		var value T
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return
+		case value, ok = <-in:
+			if !ok {
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		submit(o.Gofer, func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r := mapper(value)
+			select {
+			case <-ctx.Done():
+			case out <- r:
+			}
+		})
+	}
+}
+
+// orderedParallelMap 为每个元素分配序号，由重排 goroutine 按序号顺序输出结果。
+func orderedParallelMap[T any, R any](ctx context.Context, in <-chan T, out chan R, workers int, mapper func(T) R, o *parallelOptions) {
+	type indexed struct {
+		seq   int
+		value R
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	window := make(chan struct{}, o.Window)
+	results := make(chan indexed, o.Window)
+
+	reorderDone := make(chan struct{})
+	go func() {
+		defer close(reorderDone)
+		defer close(out)
+		pending := make(map[int]R, o.Window)
+		next := 0
+		for r := range results {
+			pending[r.seq] = r.value
+			for {
+				v, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				next++
+				select {
+				case <-ctx.Done():
+					// 继续消费 results，防止任务阻塞
+					continue
+				case out <- v:
+				}
+				<-window
+			}
+		}
+	}()
+
+	defer func() {
+		wg.Wait()
+		close(results)
+		<-reorderDone
+	}()
+	for seq := 0; ; seq++ {
+		var value T
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return
+		case value, ok = <-in:
+			if !ok {
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case window <- struct{}{}:
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		seq := seq
+		submit(o.Gofer, func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results <- indexed{seq: seq, value: mapper(value)}
+		})
+	}
+}
+
+// submit 在 g 上执行 f，如果 g 为 nil 则创建新的 goroutine，如果 g 拒绝了任务则直接执行，
+// 如果 g 丢弃了任务则创建新的 goroutine 执行，防止等待 f 的调度 goroutine 永远阻塞。
+func submit(g gofer.Gofer, f func()) {
+	if g == nil {
+		go f()
+		return
+	}
+	err := gofer.GoDiscard(g, f, func(error) {
+		// 丢弃通知可能在其他提交者的 goroutine 中被调用，不能在其中直接执行 f
+		go f()
+	})
+	if err != nil {
+		f()
+	}
+}
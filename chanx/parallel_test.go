@@ -0,0 +1,142 @@
+package chanx
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/soyacen/goconc/gofer/sample"
+)
+
+func rangeValues(n int) []int {
+	values := make([]int, n)
+	for i := range values {
+		values[i] = i
+	}
+	return values
+}
+
+func TestParallelMapUnordered(t *testing.T) {
+	ctx := context.Background()
+	var running, peak atomic.Int32
+	out := ParallelMap(ctx, Emit(ctx, rangeValues(20)...), 4, func(v int) int {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		running.Add(-1)
+		return v * 2
+	})
+	var values []int
+	for v := range out {
+		values = append(values, v)
+	}
+	sort.Ints(values)
+	if len(values) != 20 {
+		t.Fatalf("expected 20 values, got %d", len(values))
+	}
+	for i, v := range values {
+		if v != i*2 {
+			t.Fatalf("unexpected value at %d: %d", i, v)
+		}
+	}
+	if p := peak.Load(); p > 4 {
+		t.Errorf("expected at most 4 concurrent tasks, got %d", p)
+	}
+}
+
+func TestParallelMapOrdered(t *testing.T) {
+	ctx := context.Background()
+	out := ParallelMap(ctx, Emit(ctx, rangeValues(50)...), 8, func(v int) int {
+		time.Sleep(time.Duration(50-v) * 100 * time.Microsecond)
+		return v
+	}, Ordered(16))
+	i := 0
+	for v := range out {
+		if v != i {
+			t.Fatalf("expected %d, got %d", i, v)
+		}
+		i++
+	}
+	if i != 50 {
+		t.Errorf("expected 50 values, got %d", i)
+	}
+}
+
+func TestParallelMapOnGofer(t *testing.T) {
+	ctx := context.Background()
+	g := sample.New(sample.CorePoolSize(2), sample.MaximumPoolSize(2), sample.WorkQueueSize(8))
+	defer g.Close(ctx)
+	out := ParallelMap(ctx, Emit(ctx, rangeValues(20)...), 4, func(v int) int { return v + 1 }, OnGofer(g), Ordered(4))
+	i := 0
+	for v := range out {
+		if v != i+1 {
+			t.Fatalf("expected %d, got %d", i+1, v)
+		}
+		i++
+	}
+	if i != 20 {
+		t.Errorf("expected 20 values, got %d", i)
+	}
+}
+
+func TestParallelMapOnDiscardingGofer(t *testing.T) {
+	ctx := context.Background()
+	for name, policy := range map[string]sample.RejectionPolicy{
+		"Discard":       sample.DiscardPolicy,
+		"DiscardOldest": sample.DiscardOldestPolicy,
+	} {
+		t.Run(name, func(t *testing.T) {
+			g := sample.New(sample.CorePoolSize(1), sample.MaximumPoolSize(1), sample.WorkQueueSize(1), sample.Rejection(policy))
+			defer g.Close(ctx)
+			out := ParallelMap(ctx, Emit(ctx, rangeValues(8)...), 4, func(v int) int {
+				time.Sleep(time.Millisecond)
+				return v
+			}, OnGofer(g))
+			var got []int
+			timeout := time.After(time.Second)
+			for done := false; !done; {
+				select {
+				case v, ok := <-out:
+					if !ok {
+						done = true
+						break
+					}
+					got = append(got, v)
+				case <-timeout:
+					t.Fatalf("output not closed, got %v", got)
+				}
+			}
+			sort.Ints(got)
+			for i, v := range got {
+				if v != i {
+					t.Fatalf("unexpected values: %v", got)
+				}
+			}
+			if len(got) != 8 {
+				t.Errorf("expected 8 values, got %v", got)
+			}
+		})
+	}
+}
+
+func TestParallelMapCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int)
+	out := ParallelMap(ctx, in, 2, func(v int) int { return v }, Ordered(2))
+	cancel()
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Error("expected closed channel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("output not closed after cancel")
+	}
+}
@@ -0,0 +1,250 @@
+package chanx
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+var ErrPriorityChanClosed = fmt.Errorf("chanx: priority chan is closed")
+
+// Prioritized 表示一个带优先级的输入通道，Priority 越大优先级越高。
+type Prioritized[T any] struct {
+	C        <-chan T
+	Priority int
+}
+
+// PriorityMerge 将多个带优先级的输入通道合并为一个输出通道。
+// 每次输出前总是先检查优先级更高的输入通道，只有高优先级的输入通道都没有就绪的值时，才接收低优先级的值；
+// 相同优先级的输入通道之间随机选择。所有输入通道关闭或上下文取消时，输出通道被关闭。
+func PriorityMerge[T any](ctx context.Context, ins ...Prioritized[T]) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		sorted := make([]Prioritized[T], len(ins))
+		copy(sorted, ins)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Priority > sorted[j].Priority })
+
+		// levels 按优先级从高到低分组，每组末尾为 default 分支，用于非阻塞接收
+		var levels [][]reflect.SelectCase
+		// all 包含所有输入通道，末尾为上下文取消分支，用于阻塞接收
+		all := make([]reflect.SelectCase, 0, len(sorted)+1)
+		// index 记录 levels 中每个 case 在 all 中的位置
+		var index [][]int
+		for i, in := range sorted {
+			if i == 0 || in.Priority != sorted[i-1].Priority {
+				levels = append(levels, nil)
+				index = append(index, nil)
+			}
+			c := reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(in.C)}
+			levels[len(levels)-1] = append(levels[len(levels)-1], c)
+			index[len(index)-1] = append(index[len(index)-1], len(all))
+			all = append(all, c)
+		}
+		for i := range levels {
+			levels[i] = append(levels[i], reflect.SelectCase{Dir: reflect.SelectDefault})
+		}
+		all = append(all, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())})
+		remaining := len(sorted)
+
+		// closeCase 停止监听已关闭的输入通道
+		closeCase := func(pos int) {
+			all[pos].Chan = reflect.Value{}
+			for l := range index {
+				for i, p := range index[l] {
+					if p == pos {
+						levels[l][i].Chan = reflect.Value{}
+					}
+				}
+			}
+			remaining--
+		}
+
+		for remaining > 0 {
+			var recv reflect.Value
+			received := false
+			// 按优先级从高到低尝试非阻塞接收
+			for l := 0; l < len(levels) && !received; l++ {
+				for {
+					chosen, v, ok := reflect.Select(levels[l])
+					if chosen == len(levels[l])-1 {
+						// 当前优先级没有就绪的值
+						break
+					}
+					if !ok {
+						closeCase(index[l][chosen])
+						continue
+					}
+					recv, received = v, true
+					break
+				}
+			}
+			if remaining == 0 {
+				return
+			}
+			if !received {
+				// 所有输入通道都没有就绪的值，阻塞等待任意一个
+				chosen, v, ok := reflect.Select(all)
+				if chosen == len(all)-1 {
+					return
+				}
+				if !ok {
+					closeCase(chosen)
+					continue
+				}
+				recv = v
+			}
+			value, _ := recv.Interface().(T)
+			select {
+			case <-ctx.Done():
+				return
+			case out <- value:
+			}
+		}
+	}()
+	return out
+}
+
+// PriorityChan 是一个基于堆实现的优先级通道，Pop 总是返回当前优先级最高的值。
+// 优先级由 less 决定，less(a, b) 返回 true 表示 a 比 b 优先。
+type PriorityChan[T any] struct {
+	mu     sync.Mutex
+	h      *priorityHeap[T]
+	notify chan struct{}
+	closed bool
+}
+
+// NewPriorityChan 创建一个 PriorityChan。
+func NewPriorityChan[T any](less func(a, b T) bool) *PriorityChan[T] {
+	return &PriorityChan[T]{
+		h:      &priorityHeap[T]{less: less},
+		notify: make(chan struct{}),
+	}
+}
+
+// Push 放入一个值，不会阻塞。PriorityChan 关闭后返回 ErrPriorityChanClosed。
+func (p *PriorityChan[T]) Push(v T) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return ErrPriorityChanClosed
+	}
+	heap.Push(p.h, v)
+	p.broadcast()
+	return nil
+}
+
+// Pop 取出优先级最高的值，没有值时阻塞，直到有新的值、上下文取消或 PriorityChan 关闭。
+// PriorityChan 关闭后，仍可以取出剩余的值，全部取出后返回 ErrPriorityChanClosed。
+func (p *PriorityChan[T]) Pop(ctx context.Context) (T, error) {
+	for {
+		p.mu.Lock()
+		if p.h.Len() > 0 {
+			v, _ := heap.Pop(p.h).(T)
+			p.mu.Unlock()
+			return v, nil
+		}
+		if p.closed {
+			p.mu.Unlock()
+			var zero T
+			return zero, ErrPriorityChanClosed
+		}
+		notify := p.notify
+		p.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		case <-notify:
+		}
+	}
+}
+
+// TryPop 尝试取出优先级最高的值，没有值时返回 ErrDefaultBranch，关闭且没有值时返回 ErrPriorityChanClosed。
+func (p *PriorityChan[T]) TryPop() (T, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var zero T
+	if p.h.Len() > 0 {
+		v, _ := heap.Pop(p.h).(T)
+		return v, nil
+	}
+	if p.closed {
+		return zero, ErrPriorityChanClosed
+	}
+	return zero, ErrDefaultBranch
+}
+
+// Len 返回 PriorityChan 中值的数量。
+func (p *PriorityChan[T]) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.h.Len()
+}
+
+// Close 关闭 PriorityChan，之后不能再放入值，可以重复调用。
+func (p *PriorityChan[T]) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return
+	}
+	p.closed = true
+	p.broadcast()
+}
+
+// Out 返回一个通道，按优先级顺序输出 PriorityChan 中的值。
+// PriorityChan 关闭且值全部取出，或上下文取消时，输出通道被关闭。
+func (p *PriorityChan[T]) Out(ctx context.Context) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			v, err := p.Pop(ctx)
+			if err != nil {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- v:
+			}
+		}
+	}()
+	return out
+}
+
+// broadcast 唤醒所有等待的 Pop，调用时必须持有锁。
+func (p *PriorityChan[T]) broadcast() {
+	close(p.notify)
+	p.notify = make(chan struct{})
+}
+
+// priorityHeap 实现了 heap.Interface。
+type priorityHeap[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func (h *priorityHeap[T]) Len() int { return len(h.items) }
+
+func (h *priorityHeap[T]) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+
+func (h *priorityHeap[T]) Swap(i, j int) { h.items[i], h.items[j] = h.items[j], h.items[i] }
+
+func (h *priorityHeap[T]) Push(x any) {
+	v, _ := x.(T)
+	h.items = append(h.items, v)
+}
+
+func (h *priorityHeap[T]) Pop() any {
+	n := len(h.items)
+	item := h.items[n-1]
+	var zero T
+	h.items[n-1] = zero
+	h.items = h.items[:n-1]
+	return item
+}
@@ -0,0 +1,93 @@
+package chanx
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPriorityMerge(t *testing.T) {
+	ctx := context.Background()
+	low := make(chan int, 3)
+	high := make(chan int, 3)
+	for i := 0; i < 3; i++ {
+		low <- i
+		high <- 100 + i
+	}
+	close(low)
+	close(high)
+	got := collect(PriorityMerge(ctx,
+		Prioritized[int]{C: low, Priority: 1},
+		Prioritized[int]{C: high, Priority: 10},
+	))
+	want := []int{100, 101, 102, 0, 1, 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestPriorityMergeBlocking(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	low := make(chan int)
+	out := PriorityMerge(ctx, Prioritized[int]{C: low, Priority: 1})
+	go func() { low <- 1 }()
+	select {
+	case v := <-out:
+		if v != 1 {
+			t.Errorf("expected 1, got %d", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("value not received")
+	}
+	cancel()
+	if _, ok := <-out; ok {
+		t.Error("expected closed channel")
+	}
+}
+
+func TestPriorityChan(t *testing.T) {
+	ctx := context.Background()
+	p := NewPriorityChan(func(a, b int) bool { return a > b })
+	for _, v := range []int{3, 1, 4, 1, 5} {
+		if err := p.Push(v); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if n := p.Len(); n != 5 {
+		t.Errorf("expected len 5, got %d", n)
+	}
+	p.Close()
+	if err := p.Push(9); !errors.Is(err, ErrPriorityChanClosed) {
+		t.Errorf("expected ErrPriorityChanClosed, got %v", err)
+	}
+	got := collect(p.Out(ctx))
+	want := []int{5, 4, 3, 1, 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if _, err := p.Pop(ctx); !errors.Is(err, ErrPriorityChanClosed) {
+		t.Errorf("expected ErrPriorityChanClosed, got %v", err)
+	}
+}
+
+func TestPriorityChanPopBlocks(t *testing.T) {
+	p := NewPriorityChan(func(a, b int) bool { return a < b })
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := p.Pop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+	if _, err := p.TryPop(); !errors.Is(err, ErrDefaultBranch) {
+		t.Errorf("expected ErrDefaultBranch, got %v", err)
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = p.Push(7)
+	}()
+	v, err := p.Pop(context.Background())
+	if err != nil || v != 7 {
+		t.Errorf("expected 7, got %d, %v", v, err)
+	}
+}
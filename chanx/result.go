@@ -0,0 +1,227 @@
+package chanx
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Result 表示一个值或一个错误，用于在管道中传递可能失败的处理结果。
+type Result[T any] struct {
+	// Value 处理成功时的值
+	Value T
+	// Err 处理失败时的错误，为nil表示成功
+	Err error
+}
+
+// Ok 创建一个成功的 Result。
+func Ok[T any](v T) Result[T] {
+	return Result[T]{Value: v}
+}
+
+// Fail 创建一个失败的 Result。
+func Fail[T any](err error) Result[T] {
+	return Result[T]{Err: err}
+}
+
+// Get 返回 Result 中的值和错误。
+func (r Result[T]) Get() (T, error) {
+	return r.Value, r.Err
+}
+
+// IsErr 判断 Result 是否失败。
+func (r Result[T]) IsErr() bool {
+	return r.Err != nil
+}
+
+// ErrorPolicy 定义 Result 管道在遇到错误时的处理策略。
+type ErrorPolicy int
+
+const (
+	// StopOnError 遇到第一个错误时记录错误并停止整个管道。
+	StopOnError ErrorPolicy = iota
+	// SkipOnError 跳过失败的元素，继续处理后续元素。
+	SkipOnError
+	// DeadLetterOnError 将失败的元素连同错误以 DeadLetter 发送到死信通道，继续处理后续元素。
+	DeadLetterOnError
+)
+
+// ErrorSink 按照 ErrorPolicy 处理 Result 管道中的错误，并汇总为一个错误。
+// 同一个 ErrorSink 可以被多个分支共享，使整个 chanx 图在结束时只报告一个汇总错误。
+type ErrorSink struct {
+	policy     ErrorPolicy
+	deadLetter chan<- DeadLetter[any]
+	cancel     context.CancelFunc
+	mu         sync.Mutex
+	errs       []error
+}
+
+// NewErrorSink 创建一个 ErrorSink，并返回一个派生的上下文。
+// 当策略为 StopOnError 时，遇到第一个错误后派生的上下文会被取消，管道中的各个阶段应使用该上下文。
+// deadLetter 仅在策略为 DeadLetterOnError 时使用，ErrorSink 可以被不同元素类型的分支共享，因此死信的 Value 为 any。
+func NewErrorSink(ctx context.Context, policy ErrorPolicy, deadLetter chan<- DeadLetter[any]) (*ErrorSink, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &ErrorSink{policy: policy, deadLetter: deadLetter, cancel: cancel}, ctx
+}
+
+// Handle 按照策略处理元素 value 的错误 err，返回 false 表示管道应当停止。
+func (s *ErrorSink) Handle(ctx context.Context, value any, err error) bool {
+	switch s.policy {
+	case SkipOnError:
+		return true
+	case DeadLetterOnError:
+		if s.deadLetter == nil {
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			return false
+		case s.deadLetter <- DeadLetter[any]{Value: value, Err: err, Attempts: 1}:
+			return true
+		}
+	default:
+		s.mu.Lock()
+		s.errs = append(s.errs, err)
+		s.mu.Unlock()
+		s.cancel()
+		return false
+	}
+}
+
+// Err 返回记录的所有错误的汇总，没有错误时返回 nil。
+func (s *ErrorSink) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return errors.Join(s.errs...)
+}
+
+// Stop 取消 NewErrorSink 返回的上下文，释放相关资源。
+func (s *ErrorSink) Stop() {
+	s.cancel()
+}
+
+// Settle 按照 sink 的策略拆解 Result 通道，返回只包含成功值的通道。
+// 失败的 Result 中的 Value 作为失败的元素交给 sink。
+func Settle[T any](ctx context.Context, in <-chan Result[T], sink *ErrorSink) <-chan T {
+	out := make(chan T, cap(in))
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case r, ok := <-in:
+				if !ok {
+					return
+				}
+				if r.Err != nil {
+					if !sink.Handle(ctx, r.Value, r.Err) {
+						return
+					}
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- r.Value:
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// Lift 将普通通道转换为 Result 通道，每个值都被包装为成功的 Result。
+func Lift[T any](ctx context.Context, in <-chan T) <-chan Result[T] {
+	return Pipeline(ctx, in, Ok[T])
+}
+
+// PipelineE 是 Pipeline 的 Result 版本。
+// 失败的 Result 原样向下游传递，成功的值经过 f 处理，f 返回的错误被包装为失败的 Result。
+func PipelineE[T any, R any](ctx context.Context, in <-chan Result[T], f func(T) (R, error)) <-chan Result[R] {
+	return Pipeline(ctx, in, func(r Result[T]) Result[R] {
+		if r.Err != nil {
+			return Fail[R](r.Err)
+		}
+		v, err := f(r.Value)
+		if err != nil {
+			return Fail[R](err)
+		}
+		return Ok(v)
+	})
+}
+
+// ReduceE 是 Reduce 的 Result 版本。
+// 遇到失败的 Result 或 accumulator 返回错误时，输出失败的 Result，累积值保持不变。
+func ReduceE[T any, R any](ctx context.Context, in <-chan Result[T], identity R, accumulator func(R, T) (R, error)) <-chan Result[R] {
+	return Pipeline(ctx, in, func(r Result[T]) Result[R] {
+		if r.Err != nil {
+			return Fail[R](r.Err)
+		}
+		acc, err := accumulator(identity, r.Value)
+		if err != nil {
+			return Fail[R](err)
+		}
+		identity = acc
+		return Ok(identity)
+	})
+}
+
+// AsyncMapE 是 AsyncMap 的 Result 版本。
+func AsyncMapE[T any, R any](ctx context.Context, in <-chan Result[T], mapper func(T) (R, error)) <-chan Result[R] {
+	return AsyncMap(ctx, in, func(r Result[T]) Result[R] {
+		if r.Err != nil {
+			return Fail[R](r.Err)
+		}
+		v, err := mapper(r.Value)
+		if err != nil {
+			return Fail[R](err)
+		}
+		return Ok(v)
+	})
+}
+
+// FilterE 是 Filter 的 Result 版本。
+// 失败的 Result 总是向下游传递，predicate 返回错误时输出带有原值的失败的 Result。
+func FilterE[T any](in <-chan Result[T], predicate func(value T) (bool, error)) <-chan Result[T] {
+	out := make(chan Result[T])
+	go func() {
+		defer close(out)
+		for r := range in {
+			if r.Err != nil {
+				out <- r
+				continue
+			}
+			ok, err := predicate(r.Value)
+			if err != nil {
+				out <- Result[T]{Value: r.Value, Err: err}
+				continue
+			}
+			if !ok {
+				continue
+			}
+			out <- r
+		}
+	}()
+	return out
+}
+
+// AsSliceE 是 AsSlice 的 Result 版本，收集所有成功的值，并将所有错误汇总为一个错误。
+func AsSliceE[T any](ctx context.Context, in <-chan Result[T]) <-chan Result[[]T] {
+	out := make(chan Result[[]T], 1)
+	go func() {
+		defer close(out)
+		rs := <-AsSlice(ctx, in)
+		values := make([]T, 0, len(rs))
+		var errs []error
+		for _, r := range rs {
+			if r.Err != nil {
+				errs = append(errs, r.Err)
+				continue
+			}
+			values = append(values, r.Value)
+		}
+		out <- Result[[]T]{Value: values, Err: errors.Join(errs...)}
+	}()
+	return out
+}
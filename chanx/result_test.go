@@ -0,0 +1,125 @@
+package chanx
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestPipelineE(t *testing.T) {
+	ctx := context.Background()
+	in := Lift(ctx, Emit(ctx, "1", "x", "3"))
+	out := PipelineE(ctx, in, strconv.Atoi)
+	var values []int
+	var errs int
+	for r := range out {
+		if r.IsErr() {
+			errs++
+			continue
+		}
+		values = append(values, r.Value)
+	}
+	if errs != 1 {
+		t.Errorf("expected 1 error, got %d", errs)
+	}
+	if len(values) != 2 || values[0] != 1 || values[1] != 3 {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
+
+func TestAsyncMapE(t *testing.T) {
+	in := make(chan Result[string])
+	out := AsyncMapE(context.Background(), in, strconv.Atoi)
+	go func() {
+		defer close(in)
+		for _, s := range []string{"1", "x", "3"} {
+			in <- Ok(s)
+		}
+	}()
+	var values []int
+	var errs int
+	for r := range out {
+		if r.IsErr() {
+			errs++
+			continue
+		}
+		values = append(values, r.Value)
+	}
+	if errs != 1 {
+		t.Errorf("expected 1 error, got %d", errs)
+	}
+	if len(values) != 2 || values[0] != 1 || values[1] != 3 {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
+
+func TestSettleStopOnError(t *testing.T) {
+	errBoom := errors.New("boom")
+	sink, ctx := NewErrorSink(context.Background(), StopOnError, nil)
+	defer sink.Stop()
+	in := Emit(ctx, Ok(1), Fail[int](errBoom), Ok(3))
+	var values []int
+	for v := range Settle(ctx, in, sink) {
+		values = append(values, v)
+	}
+	if len(values) != 1 || values[0] != 1 {
+		t.Errorf("unexpected values: %v", values)
+	}
+	if !errors.Is(sink.Err(), errBoom) {
+		t.Errorf("expected %v, got %v", errBoom, sink.Err())
+	}
+	if ctx.Err() == nil {
+		t.Error("expected context to be cancelled")
+	}
+}
+
+func TestSettleSkipOnError(t *testing.T) {
+	sink, ctx := NewErrorSink(context.Background(), SkipOnError, nil)
+	defer sink.Stop()
+	in := Emit(ctx, Ok(1), Fail[int](errors.New("boom")), Ok(3))
+	var values []int
+	for v := range Settle(ctx, in, sink) {
+		values = append(values, v)
+	}
+	if len(values) != 2 {
+		t.Errorf("unexpected values: %v", values)
+	}
+	if sink.Err() != nil {
+		t.Errorf("expected no error, got %v", sink.Err())
+	}
+}
+
+func TestSettleDeadLetter(t *testing.T) {
+	errBoom := errors.New("boom")
+	deadLetter := make(chan DeadLetter[any], 1)
+	sink, ctx := NewErrorSink(context.Background(), DeadLetterOnError, deadLetter)
+	defer sink.Stop()
+	in := Emit(ctx, Ok(1), Result[int]{Value: 2, Err: errBoom}, Ok(3))
+	var values []int
+	for v := range Settle(ctx, in, sink) {
+		values = append(values, v)
+	}
+	if len(values) != 2 {
+		t.Errorf("unexpected values: %v", values)
+	}
+	want := DeadLetter[any]{Value: 2, Err: errBoom, Attempts: 1}
+	if got := <-deadLetter; got != want {
+		t.Errorf("expected dead letter %v, got %v", want, got)
+	}
+}
+
+func TestAsSliceEWithFanIn(t *testing.T) {
+	ctx := context.Background()
+	err1 := errors.New("err1")
+	err2 := errors.New("err2")
+	a := Emit(ctx, Ok(1), Fail[int](err1))
+	b := Emit(ctx, Ok(2), Fail[int](err2))
+	r := <-AsSliceE(ctx, FanIn(ctx, a, b))
+	if len(r.Value) != 2 {
+		t.Errorf("unexpected values: %v", r.Value)
+	}
+	if !errors.Is(r.Err, err1) || !errors.Is(r.Err, err2) {
+		t.Errorf("expected aggregated error, got %v", r.Err)
+	}
+}
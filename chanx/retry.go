@@ -0,0 +1,207 @@
+package chanx
+
+import (
+	"context"
+	"time"
+
+	"github.com/soyacen/goconc/waiter"
+)
+
+// DeadLetter 表示一个处理失败的元素，由 PipelineRetry 和 DeadLetterOnError 策略的 ErrorSink 发送到死信通道。
+type DeadLetter[T any] struct {
+	// Value 处理失败的元素
+	Value T
+	// Err 最后一次处理失败的原因
+	Err error
+	// Attempts 处理的总次数
+	Attempts int
+}
+
+// Backoff 返回第 attempt 次重试前需要等待的时长，attempt 从 1 开始。
+type Backoff func(attempt int) time.Duration
+
+// ConstantBackoff 返回每次重试前都等待 d 的 Backoff。
+func ConstantBackoff(d time.Duration) Backoff {
+	return func(int) time.Duration {
+		return d
+	}
+}
+
+// ExponentialBackoff 返回指数退避的 Backoff，第 n 次重试前等待 base * 2^(n-1)，最长不超过 max。
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt; i++ {
+			d *= 2
+			if d >= max || d <= 0 {
+				return max
+			}
+		}
+		if d > max {
+			return max
+		}
+		return d
+	}
+}
+
+const (
+	// defaultRetryAttempts 未设置 Attempts 时每个元素最多处理的次数
+	defaultRetryAttempts = 3
+	// minForeverBackoff 无限重试时每次重试前最少等待的时长，避免失败的元素空转
+	minForeverBackoff = 10 * time.Millisecond
+)
+
+type retryOptions struct {
+	Attempts int
+
+	Forever bool
+
+	Backoff Backoff
+
+	Retryable func(err error) bool
+}
+
+// RetryOption 用于配置 PipelineRetry
+type RetryOption func(*retryOptions)
+
+// Attempts 设置每个元素最多处理的次数，默认为 3，n 小于等于 0 时使用默认值。需要无限重试时使用 RetryForever。
+func Attempts(n int) RetryOption {
+	return func(o *retryOptions) {
+		o.Attempts = n
+	}
+}
+
+// RetryForever 对失败的元素无限重试，直到成功、遇到不可重试的错误或上下文取消，会覆盖 Attempts。
+// 每次重试前至少等待 10ms，即使 Backoff 返回更短的时长。
+func RetryForever() RetryOption {
+	return func(o *retryOptions) {
+		o.Forever = true
+	}
+}
+
+// WithBackoff 设置重试的退避策略。
+func WithBackoff(backoff Backoff) RetryOption {
+	return func(o *retryOptions) {
+		o.Backoff = backoff
+	}
+}
+
+// RetryIf 设置判断错误是否可以重试的函数，返回 false 的错误不再重试，直接进入死信通道。
+func RetryIf(f func(err error) bool) RetryOption {
+	return func(o *retryOptions) {
+		o.Retryable = f
+	}
+}
+
+func (o *retryOptions) Apply(opts ...RetryOption) *retryOptions {
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func (o *retryOptions) Correct() *retryOptions {
+	if o.Attempts <= 0 {
+		o.Attempts = defaultRetryAttempts
+	}
+	if o.Backoff == nil {
+		o.Backoff = ConstantBackoff(0)
+	}
+	if o.Forever {
+		// waiter.Retry 中 0 表示无限重试
+		o.Attempts = 0
+		backoff := o.Backoff
+		o.Backoff = func(attempt int) time.Duration {
+			return max(backoff(attempt), minForeverBackoff)
+		}
+	}
+	if o.Retryable == nil {
+		o.Retryable = func(error) bool { return true }
+	}
+	return o
+}
+
+// PipelineRetry 是 Pipeline 的可重试版本，f 返回错误时按照 waiter.Retry 的语义对该元素重试，
+// 每次重试前按照 Backoff 等待。最终失败的元素连同失败原因和处理次数发送到死信通道。
+// 调用者需要同时消费输出通道和死信通道。输入通道关闭或上下文取消时，两个通道都被关闭。
+func PipelineRetry[T any, R any](ctx context.Context, in <-chan T, f func(context.Context, T) (R, error), opts ...RetryOption) (<-chan R, <-chan DeadLetter[T]) {
+	o := new(retryOptions).Apply(opts...).Correct()
+	out := make(chan R, len(in))
+	deadLetter := make(chan DeadLetter[T])
+	go func() {
+		defer close(out)
+		defer close(deadLetter)
+		for {
+			var value T
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				value = v
+			}
+			result, attempts, err := retry(ctx, value, f, o)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				select {
+				case <-ctx.Done():
+					return
+				case deadLetter <- DeadLetter[T]{Value: value, Err: err, Attempts: attempts}:
+				}
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- result:
+			}
+		}
+	}()
+	return out, deadLetter
+}
+
+// retry 使用 waiter.Retry 对单个元素重试，返回结果、处理次数和最后一次的错误。
+func retry[T any, R any](ctx context.Context, value T, f func(context.Context, T) (R, error), o *retryOptions) (R, int, error) {
+	var result R
+	var attempts int
+	// permanent 记录不可重试的错误，用于提前结束 waiter.Retry
+	var permanent error
+	_, err := waiter.Retry(ctx, o.Attempts, func(ctx context.Context) (interface{}, error) {
+		if attempts > 0 {
+			if err := sleep(ctx, o.Backoff(attempts)); err != nil {
+				return nil, err
+			}
+		}
+		attempts++
+		r, err := f(ctx, value)
+		if err != nil && !o.Retryable(err) {
+			permanent = err
+			return nil, nil
+		}
+		result = r
+		return nil, err
+	})
+	if permanent != nil {
+		return result, attempts, permanent
+	}
+	return result, attempts, err
+}
+
+// sleep 等待 d，上下文取消时提前返回上下文的错误。
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := ClockFrom(ctx).NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C():
+		return nil
+	}
+}
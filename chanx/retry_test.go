@@ -0,0 +1,108 @@
+package chanx
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPipelineRetry(t *testing.T) {
+	ctx := context.Background()
+	errTransient := errors.New("transient")
+	errPermanent := errors.New("permanent")
+	var mu sync.Mutex
+	calls := make(map[int]int)
+	out, deadLetter := PipelineRetry(ctx, Emit(ctx, 1, 2, 3, 4), func(ctx context.Context, v int) (int, error) {
+		mu.Lock()
+		calls[v]++
+		n := calls[v]
+		mu.Unlock()
+		switch v {
+		case 2:
+			// 第3次成功
+			if n < 3 {
+				return 0, errTransient
+			}
+		case 3:
+			return 0, errTransient
+		case 4:
+			return 0, errPermanent
+		}
+		return v * 10, nil
+	}, Attempts(3), WithBackoff(ConstantBackoff(time.Millisecond)), RetryIf(func(err error) bool {
+		return !errors.Is(err, errPermanent)
+	}))
+
+	var dead []DeadLetter[int]
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		dead = collect(deadLetter)
+	}()
+	got := collect(out)
+	<-done
+
+	if !reflect.DeepEqual(got, []int{10, 20}) {
+		t.Errorf("unexpected values: %v", got)
+	}
+	want := []DeadLetter[int]{
+		{Value: 3, Err: errTransient, Attempts: 3},
+		{Value: 4, Err: errPermanent, Attempts: 1},
+	}
+	if !reflect.DeepEqual(dead, want) {
+		t.Errorf("expected dead letters %v, got %v", want, dead)
+	}
+}
+
+func TestPipelineRetryCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out, deadLetter := PipelineRetry(ctx, Emit(ctx, 1), func(ctx context.Context, v int) (int, error) {
+		return 0, errors.New("always")
+	}, RetryForever())
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	AsyncDiscard(deadLetter)
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Error("expected closed channel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("output not closed after cancel")
+	}
+}
+
+func TestPipelineRetryDefaults(t *testing.T) {
+	ctx := context.Background()
+	errAlways := errors.New("always")
+	out, deadLetter := PipelineRetry(ctx, Emit(ctx, 1), func(ctx context.Context, v int) (int, error) {
+		return 0, errAlways
+	})
+	AsyncDiscard(out)
+	dead := collect(deadLetter)
+	want := []DeadLetter[int]{{Value: 1, Err: errAlways, Attempts: defaultRetryAttempts}}
+	if !reflect.DeepEqual(dead, want) {
+		t.Errorf("expected dead letters %v, got %v", want, dead)
+	}
+
+	o := new(retryOptions).Apply(Attempts(5), WithBackoff(ConstantBackoff(0)), RetryForever()).Correct()
+	if o.Attempts != 0 {
+		t.Errorf("expected unlimited attempts, got %d", o.Attempts)
+	}
+	if d := o.Backoff(1); d < minForeverBackoff {
+		t.Errorf("expected backoff of at least %v, got %v", minForeverBackoff, d)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond}
+	for i, w := range want {
+		if d := b(i + 1); d != w {
+			t.Errorf("attempt %d: expected %v, got %v", i+1, w, d)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package chanx
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Throttle 基于令牌桶限制输出速率，每个 interval 最多输出 n 个元素，允许最多 n 个元素的突发。
+// 令牌不足时等待，不丢弃元素。输入通道关闭或上下文取消时，输出通道被关闭。
+func Throttle[T any](ctx context.Context, in <-chan T, n int, interval time.Duration) <-chan T {
+	var out chan T
+	if in == nil {
+		return out
+	}
+	if n <= 0 {
+		n = 1
+	}
+	out = make(chan T)
+	go func() {
+		defer close(out)
+		// 每个令牌的生成间隔
+		per := interval / time.Duration(n)
+		if per <= 0 {
+			per = 1
+		}
+		// 令牌桶初始为满，允许 n 个元素的突发
+		tokens := float64(n)
+		clock := ClockFrom(ctx)
+		last := clock.Now()
+		refill := func() {
+			now := clock.Now()
+			tokens = math.Min(float64(n), tokens+float64(now.Sub(last))/float64(per))
+			last = now
+		}
+		timer := clock.NewTimer(per)
+		defer timer.Stop()
+		stopTimer(timer)
+		for {
+			var value T
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				value = v
+			}
+			refill()
+			if tokens < 1 {
+				timer.Reset(time.Duration((1 - tokens) * float64(per)))
+				select {
+				case <-ctx.Done():
+					return
+				case <-timer.C():
+				}
+				refill()
+				tokens = math.Max(tokens, 1)
+			}
+			tokens--
+			select {
+			case <-ctx.Done():
+				return
+			case out <- value:
+			}
+		}
+	}()
+	return out
+}
+
+// Debounce 只有在输入通道安静 quiet 时长后才输出最后收到的元素，期间收到的新元素会覆盖旧元素并重新计时。
+// 输入通道关闭时，输出尚未发送的最后一个元素。
+func Debounce[T any](ctx context.Context, in <-chan T, quiet time.Duration) <-chan T {
+	var out chan T
+	if in == nil {
+		return out
+	}
+	out = make(chan T)
+	go func() {
+		defer close(out)
+		timer := ClockFrom(ctx).NewTimer(quiet)
+		defer timer.Stop()
+		stopTimer(timer)
+		var timerC <-chan time.Time
+		var latest T
+		var pending bool
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					if pending {
+						select {
+						case <-ctx.Done():
+						case out <- latest:
+						}
+					}
+					return
+				}
+				latest, pending = v, true
+				stopTimer(timer)
+				timer.Reset(quiet)
+				timerC = timer.C()
+			case <-timerC:
+				timerC = nil
+				pending = false
+				select {
+				case <-ctx.Done():
+					return
+				case out <- latest:
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// Sample 每隔 interval 输出一次最近收到的元素，如果上个周期内没有收到新元素则不输出。
+// interval 小于等于 0 时不采样，原样输出每个元素。
+// 输入通道关闭时，输出尚未发送的最后一个元素。
+func Sample[T any](ctx context.Context, in <-chan T, interval time.Duration) <-chan T {
+	var out chan T
+	if in == nil {
+		return out
+	}
+	if interval <= 0 {
+		return Pipeline(ctx, in, func(v T) T { return v })
+	}
+	out = make(chan T)
+	go func() {
+		defer close(out)
+		ticker := ClockFrom(ctx).NewTicker(interval)
+		defer ticker.Stop()
+		var latest T
+		var pending bool
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					if pending {
+						select {
+						case <-ctx.Done():
+						case out <- latest:
+						}
+					}
+					return
+				}
+				latest, pending = v, true
+			case <-ticker.C():
+				if !pending {
+					continue
+				}
+				pending = false
+				select {
+				case <-ctx.Done():
+					return
+				case out <- latest:
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// Delay 将每个元素延迟 d 后输出，延迟从元素被接收时开始计算，元素的顺序保持不变。
+// 输入通道关闭时，剩余的元素仍会在各自的延迟到期后输出，之后输出通道被关闭。
+func Delay[T any](ctx context.Context, in <-chan T, d time.Duration) <-chan T {
+	var out chan T
+	if in == nil {
+		return out
+	}
+	out = make(chan T)
+	go func() {
+		type delayed struct {
+			value T
+			at    time.Time
+		}
+		defer close(out)
+		queue := newRing[delayed](16)
+		clock := ClockFrom(ctx)
+		timer := clock.NewTimer(d)
+		defer timer.Stop()
+		stopTimer(timer)
+		var timerC <-chan time.Time
+		var outC chan T
+		for in != nil || queue.Len() > 0 {
+			var next T
+			if queue.Len() > 0 && timerC == nil && outC == nil {
+				if wait := queue.Peek().at.Sub(clock.Now()); wait > 0 {
+					timer.Reset(wait)
+					timerC = timer.C()
+				} else {
+					outC = out
+				}
+			}
+			if outC != nil {
+				next = queue.Peek().value
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok := <-in:
+				if !ok {
+					in = nil
+					continue
+				}
+				queue.Push(delayed{value: v, at: clock.Now().Add(d)})
+			case <-timerC:
+				timerC = nil
+				outC = out
+			case outC <- next:
+				queue.Pop()
+				outC = nil
+			}
+		}
+	}()
+	return out
+}
+
+// stopTimer 停止 timer 并清理已触发但未被接收的值。
+func stopTimer(timer Timer) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C():
+		default:
+		}
+	}
+}
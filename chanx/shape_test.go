@@ -0,0 +1,88 @@
+package chanx
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestThrottle(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now()
+	got := collect(Throttle(ctx, Emit(ctx, rangeValues(6)...), 2, 40*time.Millisecond))
+	elapsed := time.Since(start)
+	if !reflect.DeepEqual(got, rangeValues(6)) {
+		t.Errorf("unexpected values: %v", got)
+	}
+	// 前2个为突发，后4个需要等待 4 * 20ms
+	if elapsed < 70*time.Millisecond {
+		t.Errorf("expected throttling, elapsed %v", elapsed)
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	ctx := context.Background()
+	in := make(chan int)
+	out := Debounce(ctx, in, 30*time.Millisecond)
+	in <- 1
+	in <- 2
+	in <- 3
+	select {
+	case v := <-out:
+		if v != 3 {
+			t.Errorf("expected 3, got %d", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("debounced value not emitted")
+	}
+	in <- 4
+	close(in)
+	if v := <-out; v != 4 {
+		t.Errorf("expected pending value 4 on close, got %d", v)
+	}
+	if _, ok := <-out; ok {
+		t.Error("expected closed channel")
+	}
+}
+
+func TestSample(t *testing.T) {
+	ctx := context.Background()
+	in := make(chan int)
+	out := Sample(ctx, in, 20*time.Millisecond)
+	in <- 1
+	in <- 2
+	select {
+	case v := <-out:
+		if v != 2 && v != 1 {
+			t.Errorf("unexpected sample %d", v)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("sample not emitted")
+	}
+	close(in)
+	Discard(out)
+}
+
+func TestSampleNonPositiveInterval(t *testing.T) {
+	ctx := context.Background()
+	var values []int
+	for v := range Sample(ctx, Emit(ctx, 1, 2, 3), 0) {
+		values = append(values, v)
+	}
+	if !reflect.DeepEqual(values, []int{1, 2, 3}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
+
+func TestDelay(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now()
+	got := collect(Delay(ctx, Emit(ctx, 1, 2, 3), 30*time.Millisecond))
+	if !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("unexpected values: %v", got)
+	}
+	if elapsed := time.Since(start); elapsed < 30*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Errorf("unexpected elapsed %v", elapsed)
+	}
+}
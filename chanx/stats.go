@@ -0,0 +1,238 @@
+package chanx
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Number 是可以计算统计量的数值类型。
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
+// Scan 对输入通道中的元素做累积计算，从 seed 开始，每收到一个元素都输出一次当前的累积值。
+// 输入通道关闭或上下文取消时，输出通道被关闭。
+func Scan[T any, R any](ctx context.Context, in <-chan T, seed R, accumulator func(acc R, value T) R) <-chan R {
+	acc := seed
+	return Pipeline(ctx, in, func(value T) R {
+		acc = accumulator(acc, value)
+		return acc
+	})
+}
+
+// Fold 对输入通道中的元素做累积计算，只在输入通道关闭后输出最终的累积值。
+// 如果在输入通道关闭前上下文被取消，输出通道被关闭且不输出任何值。
+func Fold[T any, R any](ctx context.Context, in <-chan T, seed R, accumulator func(acc R, value T) R) <-chan R {
+	out := make(chan R, 1)
+	go func() {
+		defer close(out)
+		acc := seed
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case value, ok := <-in:
+				if !ok {
+					out <- acc
+					return
+				}
+				acc = accumulator(acc, value)
+			}
+		}
+	}()
+	return out
+}
+
+// Summary 是一组数值的统计摘要。
+type Summary struct {
+	Count int
+	Sum   float64
+	Mean  float64
+	Min   float64
+	Max   float64
+	// Quantiles 保存请求的分位数，键为分位数（如 0.99），值为基于 Sketch 的近似结果
+	Quantiles map[float64]float64
+}
+
+// RunningStats 增量地计算数值的数量、总和、均值、最小值、最大值，并通过 Sketch 估算分位数。非并发安全。
+type RunningStats struct {
+	count  int
+	sum    float64
+	min    float64
+	max    float64
+	sketch *Sketch
+}
+
+// NewRunningStats 创建一个 RunningStats，relativeAccuracy 为分位数的相对误差，参见 NewSketch。
+func NewRunningStats(relativeAccuracy float64) *RunningStats {
+	return &RunningStats{sketch: NewSketch(relativeAccuracy)}
+}
+
+// Add 添加一个数值，与 Sketch.Add 一致，NaN 会被忽略。
+func (s *RunningStats) Add(v float64) {
+	if math.IsNaN(v) {
+		return
+	}
+	if s.count == 0 || v < s.min {
+		s.min = v
+	}
+	if s.count == 0 || v > s.max {
+		s.max = v
+	}
+	s.count++
+	s.sum += v
+	s.sketch.Add(v)
+}
+
+// Summary 返回当前的统计摘要，quantiles 为需要估算的分位数，取值范围为 [0, 1]。
+func (s *RunningStats) Summary(quantiles ...float64) Summary {
+	summary := Summary{
+		Count:     s.count,
+		Sum:       s.sum,
+		Min:       s.min,
+		Max:       s.max,
+		Quantiles: make(map[float64]float64, len(quantiles)),
+	}
+	if s.count > 0 {
+		summary.Mean = s.sum / float64(s.count)
+	}
+	for _, q := range quantiles {
+		summary.Quantiles[q] = s.sketch.Quantile(q)
+	}
+	return summary
+}
+
+// TumblingStats 按固定时间窗口 d 计算输入通道中数值的统计摘要，每个非空窗口输出一个 Summary。
+// d 小于等于 0 时与 TumblingWindow 相同，每个元素单独作为一个窗口。
+func TumblingStats[T Number](ctx context.Context, in <-chan T, d time.Duration, quantiles ...float64) <-chan Summary {
+	return Pipeline(ctx, TumblingWindow(ctx, in, d), func(window []T) Summary {
+		return summarize(window, quantiles)
+	})
+}
+
+// SlidingStats 按数量滑动窗口计算输入通道中数值的统计摘要，窗口大小为 size，每收到 step 个新元素输出一个 Summary。
+func SlidingStats[T Number](ctx context.Context, in <-chan T, size, step int, quantiles ...float64) <-chan Summary {
+	return Pipeline(ctx, SlidingWindow(ctx, in, size, step), func(window []T) Summary {
+		return summarize(window, quantiles)
+	})
+}
+
+func summarize[T Number](window []T, quantiles []float64) Summary {
+	stats := NewRunningStats(defaultRelativeAccuracy)
+	for _, v := range window {
+		stats.Add(float64(v))
+	}
+	return stats.Summary(quantiles...)
+}
+
+const defaultRelativeAccuracy = 0.01
+
+// Sketch 是一个基于对数分桶的分位数草图（参考 DDSketch），估算的分位数相对误差不超过 relativeAccuracy，
+// 占用的空间只与数值的数量级范围有关。非并发安全。
+type Sketch struct {
+	gamma    float64
+	logGamma float64
+	positive map[int]uint64
+	negative map[int]uint64
+	zero     uint64
+	count    uint64
+}
+
+// NewSketch 创建一个 Sketch，relativeAccuracy 的取值范围为 (0, 1)，不合法时使用 0.01。
+func NewSketch(relativeAccuracy float64) *Sketch {
+	if relativeAccuracy <= 0 || relativeAccuracy >= 1 {
+		relativeAccuracy = defaultRelativeAccuracy
+	}
+	gamma := (1 + relativeAccuracy) / (1 - relativeAccuracy)
+	return &Sketch{
+		gamma:    gamma,
+		logGamma: math.Log(gamma),
+		positive: make(map[int]uint64),
+		negative: make(map[int]uint64),
+	}
+}
+
+// Add 添加一个数值，NaN 会被忽略。
+func (s *Sketch) Add(v float64) {
+	switch {
+	case math.IsNaN(v):
+		return
+	case v > 0:
+		s.positive[s.index(v)]++
+	case v < 0:
+		s.negative[s.index(-v)]++
+	default:
+		s.zero++
+	}
+	s.count++
+}
+
+// Count 返回添加的数值的数量。
+func (s *Sketch) Count() uint64 {
+	return s.count
+}
+
+// Merge 将 other 中的数值合并到 s 中，两者的相对误差必须相同。
+func (s *Sketch) Merge(other *Sketch) {
+	for k, n := range other.positive {
+		s.positive[k] += n
+	}
+	for k, n := range other.negative {
+		s.negative[k] += n
+	}
+	s.zero += other.zero
+	s.count += other.count
+}
+
+// Quantile 返回分位数 q 的近似值，q 的取值范围为 [0, 1]，没有数值时返回 NaN。
+func (s *Sketch) Quantile(q float64) float64 {
+	if s.count == 0 || q < 0 || q > 1 {
+		return math.NaN()
+	}
+	rank := uint64(q * float64(s.count-1))
+	var seen uint64
+	// 负数按绝对值从大到小，即数值从小到大
+	for _, k := range sortedKeys(s.negative, true) {
+		seen += s.negative[k]
+		if seen > rank {
+			return -s.value(k)
+		}
+	}
+	seen += s.zero
+	if seen > rank {
+		return 0
+	}
+	for _, k := range sortedKeys(s.positive, false) {
+		seen += s.positive[k]
+		if seen > rank {
+			return s.value(k)
+		}
+	}
+	return math.NaN()
+}
+
+func (s *Sketch) index(v float64) int {
+	return int(math.Ceil(math.Log(v) / s.logGamma))
+}
+
+// value 返回桶 k 的代表值，使桶内任意值的相对误差都不超过 relativeAccuracy。
+func (s *Sketch) value(k int) float64 {
+	return 2 * math.Pow(s.gamma, float64(k)) / (s.gamma + 1)
+}
+
+func sortedKeys(m map[int]uint64, desc bool) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	if desc {
+		sort.Sort(sort.Reverse(sort.IntSlice(keys)))
+	} else {
+		sort.Ints(keys)
+	}
+	return keys
+}
@@ -0,0 +1,94 @@
+package chanx
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScanAndFold(t *testing.T) {
+	ctx := context.Background()
+	sum := func(acc, v int) int { return acc + v }
+	got := collect(Scan(ctx, Emit(ctx, 1, 2, 3, 4), 0, sum))
+	if !reflect.DeepEqual(got, []int{1, 3, 6, 10}) {
+		t.Errorf("unexpected values: %v", got)
+	}
+	got = collect(Fold(ctx, Emit(ctx, 1, 2, 3, 4), 0, sum))
+	if !reflect.DeepEqual(got, []int{10}) {
+		t.Errorf("unexpected values: %v", got)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if got := collect(Fold(cancelled, make(chan int), 0, sum)); len(got) != 0 {
+		t.Errorf("expected no value after cancel, got %v", got)
+	}
+}
+
+func TestSketchQuantile(t *testing.T) {
+	s := NewSketch(0.01)
+	for i := 1; i <= 1000; i++ {
+		s.Add(float64(i))
+	}
+	for _, tc := range []struct{ q, want float64 }{{0, 1}, {0.5, 500}, {0.99, 990}, {1, 1000}} {
+		got := s.Quantile(tc.q)
+		if math.Abs(got-tc.want)/tc.want > 0.02 {
+			t.Errorf("quantile %v: expected about %v, got %v", tc.q, tc.want, got)
+		}
+	}
+
+	neg := NewSketch(0.01)
+	for _, v := range []float64{-10, -5, 0, 5, 10} {
+		neg.Add(v)
+	}
+	if got := neg.Quantile(0); math.Abs(got+10) > 0.2 {
+		t.Errorf("expected about -10, got %v", got)
+	}
+	if got := neg.Quantile(0.5); got != 0 {
+		t.Errorf("expected 0, got %v", got)
+	}
+	if !math.IsNaN(NewSketch(0.01).Quantile(0.5)) {
+		t.Error("expected NaN for empty sketch")
+	}
+}
+
+func TestRunningStatsSkipsNaN(t *testing.T) {
+	s := NewRunningStats(0.01)
+	for _, v := range []float64{math.NaN(), 2, math.NaN(), 4} {
+		s.Add(v)
+	}
+	summary := s.Summary(0.5)
+	if summary.Count != 2 || summary.Sum != 6 || summary.Mean != 3 || summary.Min != 2 || summary.Max != 4 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestSlidingStats(t *testing.T) {
+	ctx := context.Background()
+	got := collect(SlidingStats(ctx, Emit(ctx, 1, 2, 3, 4), 2, 2, 0.5))
+	if len(got) != 2 {
+		t.Fatalf("expected 2 summaries, got %d", len(got))
+	}
+	s := got[1]
+	if s.Count != 2 || s.Sum != 7 || s.Mean != 3.5 || s.Min != 3 || s.Max != 4 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+	if q := s.Quantiles[0.5]; math.Abs(q-3) > 0.1 {
+		t.Errorf("expected median about 3, got %v", q)
+	}
+}
+
+func TestTumblingStats(t *testing.T) {
+	ctx := context.Background()
+	in := make(chan float64)
+	out := TumblingStats(ctx, in, time.Hour)
+	in <- 1
+	in <- 3
+	close(in)
+	s := <-out
+	if s.Count != 2 || s.Mean != 2 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+}
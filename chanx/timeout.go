@@ -0,0 +1,133 @@
+package chanx
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+var ErrTimeout = fmt.Errorf("chanx: timeout waiting for element")
+
+// Timeout 将输入通道转换为 Result 通道，如果超过 d 没有收到新元素，输出一个错误为 ErrTimeout 的 Result 并关闭输出通道。
+// 计时从开始等待每个元素时重新开始。输入通道关闭或上下文取消时，输出通道被关闭。
+func Timeout[T any](ctx context.Context, in <-chan T, d time.Duration) <-chan Result[T] {
+	var out chan Result[T]
+	if in == nil {
+		return out
+	}
+	out = make(chan Result[T])
+	go func() {
+		defer close(out)
+		timer := ClockFrom(ctx).NewTimer(d)
+		defer timer.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C():
+				select {
+				case <-ctx.Done():
+				case out <- Fail[T](ErrTimeout):
+				}
+				return
+			case value, ok := <-in:
+				if !ok {
+					return
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- Ok(value):
+				}
+				stopTimer(timer)
+				timer.Reset(d)
+			}
+		}
+	}()
+	return out
+}
+
+// TimeoutWith 如果超过 d 没有收到新元素，输出 fallback 返回的值作为哨兵，然后重新计时并继续等待。
+// 输入通道关闭或上下文取消时，输出通道被关闭。
+func TimeoutWith[T any](ctx context.Context, in <-chan T, d time.Duration, fallback func() T) <-chan T {
+	var out chan T
+	if in == nil {
+		return out
+	}
+	out = make(chan T)
+	go func() {
+		defer close(out)
+		timer := ClockFrom(ctx).NewTimer(d)
+		defer timer.Stop()
+		for {
+			var value T
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C():
+				value = fallback()
+			case v, ok := <-in:
+				if !ok {
+					return
+				}
+				value = v
+				stopTimer(timer)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- value:
+			}
+			timer.Reset(d)
+		}
+	}()
+	return out
+}
+
+// Heartbeat 将输入通道中的元素原样输出，同时每隔 interval 向心跳通道发送一次当前时间，
+// 用于监督者检测阶段是否存活。心跳通道的缓冲区为 1，没有被及时接收的心跳会被丢弃，不会阻塞数据的传递。
+// interval 小于等于 0 时不发送心跳，只原样输出元素。
+// 输入通道关闭或上下文取消时，输出通道和心跳通道被关闭。
+func Heartbeat[T any](ctx context.Context, in <-chan T, interval time.Duration) (<-chan T, <-chan time.Time) {
+	out := make(chan T)
+	heartbeat := make(chan time.Time, 1)
+	go func() {
+		defer close(out)
+		defer close(heartbeat)
+		var tickC <-chan time.Time
+		if interval > 0 {
+			ticker := ClockFrom(ctx).NewTicker(interval)
+			defer ticker.Stop()
+			tickC = ticker.C()
+		}
+		pulse := func(now time.Time) {
+			select {
+			case heartbeat <- now:
+			default:
+			}
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-tickC:
+				pulse(now)
+			case value, ok := <-in:
+				if !ok {
+					return
+				}
+				for sent := false; !sent; {
+					select {
+					case <-ctx.Done():
+						return
+					case now := <-tickC:
+						pulse(now)
+					case out <- value:
+						sent = true
+					}
+				}
+			}
+		}
+	}()
+	return out, heartbeat
+}
@@ -0,0 +1,74 @@
+package chanx
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	ctx := context.Background()
+	in := make(chan int)
+	out := Timeout(ctx, in, 30*time.Millisecond)
+	in <- 1
+	if r := <-out; r.IsErr() || r.Value != 1 {
+		t.Errorf("unexpected result %v", r)
+	}
+	r := <-out
+	if !errors.Is(r.Err, ErrTimeout) {
+		t.Errorf("expected ErrTimeout, got %v", r.Err)
+	}
+	if _, ok := <-out; ok {
+		t.Error("expected closed channel")
+	}
+}
+
+func TestTimeoutWith(t *testing.T) {
+	ctx := context.Background()
+	in := make(chan int)
+	out := TimeoutWith(ctx, in, 20*time.Millisecond, func() int { return -1 })
+	if v := <-out; v != -1 {
+		t.Errorf("expected sentinel -1, got %d", v)
+	}
+	in <- 1
+	if v := <-out; v != 1 {
+		t.Errorf("expected 1, got %d", v)
+	}
+	close(in)
+	for range out {
+	}
+}
+
+func TestHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	in := make(chan int)
+	out, heartbeat := Heartbeat(ctx, in, 10*time.Millisecond)
+	select {
+	case <-heartbeat:
+	case <-time.After(time.Second):
+		t.Fatal("heartbeat not received")
+	}
+	in <- 1
+	if v := <-out; v != 1 {
+		t.Errorf("expected 1, got %d", v)
+	}
+	close(in)
+	if _, ok := <-out; ok {
+		t.Error("expected closed channel")
+	}
+	for range heartbeat {
+	}
+}
+
+func TestHeartbeatNonPositiveInterval(t *testing.T) {
+	ctx := context.Background()
+	out, heartbeat := Heartbeat(ctx, Emit(ctx, 1, 2), 0)
+	if got := collect(out); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("unexpected values: %v", got)
+	}
+	if _, ok := <-heartbeat; ok {
+		t.Error("expected no heartbeat")
+	}
+}
@@ -0,0 +1,190 @@
+package chanx
+
+import (
+	"context"
+	"time"
+)
+
+// Batch 将输入通道中的元素按数量分组，每 size 个元素输出一个切片。
+// 输入通道关闭时，输出剩余不足 size 个元素的切片。
+func Batch[T any](ctx context.Context, in <-chan T, size int) <-chan []T {
+	return BatchWithTimeout(ctx, in, size, 0)
+}
+
+// BatchWithTimeout 将输入通道中的元素分组，当批次达到 size 个元素，或距批次中第一个元素到达超过 timeout 时，
+// 以先到者为准输出一个切片。timeout 小于等于 0 表示不限制时间。
+// 输入通道关闭时，输出剩余的元素。
+func BatchWithTimeout[T any](ctx context.Context, in <-chan T, size int, timeout time.Duration) <-chan []T {
+	var out chan []T
+	if in == nil {
+		return out
+	}
+	if size <= 0 {
+		size = 1
+	}
+	out = make(chan []T)
+	go func() {
+		defer close(out)
+		clock := ClockFrom(ctx)
+		var timer Timer
+		var timeoutC <-chan time.Time
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+		batch := make([]T, 0, size)
+		flush := func() bool {
+			if timer != nil {
+				// 清理已触发但未被接收的值，防止下一批次立即超时
+				stopTimer(timer)
+			}
+			timeoutC = nil
+			if len(batch) == 0 {
+				return true
+			}
+			select {
+			case <-ctx.Done():
+				return false
+			case out <- batch:
+				batch = make([]T, 0, size)
+				return true
+			}
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case value, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				batch = append(batch, value)
+				if len(batch) == 1 && timeout > 0 {
+					if timer == nil {
+						timer = clock.NewTimer(timeout)
+					} else {
+						timer.Reset(timeout)
+					}
+					timeoutC = timer.C()
+				}
+				if len(batch) >= size && !flush() {
+					return
+				}
+			case <-timeoutC:
+				timeoutC = nil
+				if !flush() {
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// TumblingWindow 将输入通道中的元素按固定时间窗口分组，每隔 d 输出一个窗口内收到的元素，空窗口不输出。
+// d 小于等于 0 时每个元素单独作为一个窗口输出。
+// 输入通道关闭时，输出最后一个未满的窗口。
+func TumblingWindow[T any](ctx context.Context, in <-chan T, d time.Duration) <-chan []T {
+	var out chan []T
+	if in == nil {
+		return out
+	}
+	if d <= 0 {
+		return Batch(ctx, in, 1)
+	}
+	out = make(chan []T)
+	go func() {
+		defer close(out)
+		ticker := ClockFrom(ctx).NewTicker(d)
+		defer ticker.Stop()
+		var window []T
+		flush := func() bool {
+			if len(window) == 0 {
+				return true
+			}
+			select {
+			case <-ctx.Done():
+				return false
+			case out <- window:
+				window = nil
+				return true
+			}
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case value, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				window = append(window, value)
+			case <-ticker.C():
+				if !flush() {
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// SlidingWindow 将输入通道中的元素按数量滑动分组，窗口大小为 size，每收到 step 个新元素输出一次当前窗口。
+// 窗口填满之前不输出；输入通道关闭时，如果还有未输出过的元素，输出最后一个窗口。
+func SlidingWindow[T any](ctx context.Context, in <-chan T, size, step int) <-chan []T {
+	var out chan []T
+	if in == nil {
+		return out
+	}
+	if size <= 0 {
+		size = 1
+	}
+	if step <= 0 {
+		step = 1
+	}
+	out = make(chan []T)
+	go func() {
+		defer close(out)
+		window := make([]T, 0, size)
+		// pending 记录自上次输出后收到的元素数量
+		pending := 0
+		emit := func() bool {
+			w := make([]T, len(window))
+			copy(w, window)
+			select {
+			case <-ctx.Done():
+				return false
+			case out <- w:
+				pending = 0
+				return true
+			}
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case value, ok := <-in:
+				if !ok {
+					if pending > 0 && len(window) > 0 {
+						emit()
+					}
+					return
+				}
+				if len(window) == size {
+					window = append(window[:0], window[1:]...)
+				}
+				window = append(window, value)
+				pending++
+				if len(window) == size && pending >= step {
+					if !emit() {
+						return
+					}
+				}
+			}
+		}
+	}()
+	return out
+}
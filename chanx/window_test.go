@@ -0,0 +1,123 @@
+package chanx
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func collect[T any](c <-chan T) []T {
+	var values []T
+	for v := range c {
+		values = append(values, v)
+	}
+	return values
+}
+
+func TestBatch(t *testing.T) {
+	ctx := context.Background()
+	got := collect(Batch(ctx, Emit(ctx, 1, 2, 3, 4, 5), 2))
+	want := [][]int{{1, 2}, {3, 4}, {5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestBatchWithTimeout(t *testing.T) {
+	ctx := context.Background()
+	in := make(chan int)
+	out := BatchWithTimeout(ctx, in, 10, 20*time.Millisecond)
+	in <- 1
+	in <- 2
+	select {
+	case batch := <-out:
+		if !reflect.DeepEqual(batch, []int{1, 2}) {
+			t.Errorf("unexpected batch: %v", batch)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout batch not emitted")
+	}
+	in <- 3
+	close(in)
+	if batch := <-out; !reflect.DeepEqual(batch, []int{3}) {
+		t.Errorf("unexpected batch: %v", batch)
+	}
+	if _, ok := <-out; ok {
+		t.Error("expected closed channel")
+	}
+}
+
+func TestTumblingWindow(t *testing.T) {
+	ctx := context.Background()
+	in := make(chan int)
+	out := TumblingWindow(ctx, in, 20*time.Millisecond)
+	in <- 1
+	in <- 2
+	var values []int
+	for len(values) < 2 {
+		select {
+		case w := <-out:
+			values = append(values, w...)
+		case <-time.After(time.Second):
+			t.Fatal("window not emitted")
+		}
+	}
+	if !reflect.DeepEqual(values, []int{1, 2}) {
+		t.Errorf("unexpected values: %v", values)
+	}
+	in <- 3
+	close(in)
+	if w := <-out; !reflect.DeepEqual(w, []int{3}) {
+		t.Errorf("unexpected window: %v", w)
+	}
+	if _, ok := <-out; ok {
+		t.Error("expected closed channel")
+	}
+}
+
+func TestTumblingWindowNonPositiveDuration(t *testing.T) {
+	ctx := context.Background()
+	var windows [][]int
+	for w := range TumblingWindow(ctx, Emit(ctx, 1, 2, 3), 0) {
+		windows = append(windows, w)
+	}
+	if !reflect.DeepEqual(windows, [][]int{{1}, {2}, {3}}) {
+		t.Errorf("unexpected windows: %v", windows)
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	ctx := context.Background()
+	got := collect(SlidingWindow(ctx, Emit(ctx, 1, 2, 3, 4, 5), 3, 1))
+	want := [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	got = collect(SlidingWindow(ctx, Emit(ctx, 1, 2, 3, 4), 3, 2))
+	want = [][]int{{1, 2, 3}, {2, 3, 4}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	got = collect(SlidingWindow(ctx, Emit(ctx, 1, 2), 3, 1))
+	want = [][]int{{1, 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestBatchCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out := Batch(ctx, make(chan int), 2)
+	cancel()
+	select {
+	case _, ok := <-out:
+		if ok {
+			t.Error("expected closed channel")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("output not closed after cancel")
+	}
+}
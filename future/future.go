@@ -0,0 +1,227 @@
+// Package future provides Future and Promise types for awaiting, chaining and combining
+// the results of asynchronous calls.
+package future
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/soyacen/goconc/brave"
+)
+
+// ErrCanceled is the error of a Future that was canceled before it completed.
+var ErrCanceled = errors.New("future: canceled")
+
+// ErrNoFutures is the error of AnyOf and FirstSuccessful when called without any futures.
+var ErrNoFutures = errors.New("future: no futures")
+
+// Future represents the result of an asynchronous computation that completes exactly once,
+// either with a value or with an error.
+type Future[T any] struct {
+	done   chan struct{}
+	once   sync.Once
+	value  T
+	err    error
+	cancel context.CancelFunc
+}
+
+func newFuture[T any](cancel context.CancelFunc) *Future[T] {
+	return &Future[T]{done: make(chan struct{}), cancel: cancel}
+}
+
+// Go runs f asynchronously via brave.GoRE and returns a Future of its result.
+// A panic in f completes the Future with the error produced by brave.
+// The context passed to f is canceled when the Future is canceled or completes.
+func Go[T any](ctx context.Context, f func(ctx context.Context) (T, error)) *Future[T] {
+	ctx, cancel := context.WithCancel(ctx)
+	future := newFuture[T](cancel)
+	retC, errC := brave.GoRE(func() (T, error) {
+		return f(ctx)
+	})
+	go func() {
+		// retC is closed only after the error, if any, has been sent on errC
+		if value, ok := <-retC; ok {
+			future.complete(value, nil)
+			return
+		}
+		var zero T
+		future.complete(zero, <-errC)
+	}()
+	return future
+}
+
+// Get waits for the Future to complete and returns its value and error.
+// If ctx is done first, Get returns ctx.Err() without affecting the Future.
+func (f *Future[T]) Get(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Done returns a channel that is closed when the Future completes.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Cancel completes the Future with ErrCanceled and cancels the context of its computation.
+// It returns false if the Future had already completed.
+func (f *Future[T]) Cancel() bool {
+	var zero T
+	return f.complete(zero, ErrCanceled)
+}
+
+// complete sets the result of the Future if it has not completed yet and reports whether it did.
+func (f *Future[T]) complete(value T, err error) bool {
+	completed := false
+	f.once.Do(func() {
+		f.value, f.err = value, err
+		close(f.done)
+		completed = true
+	})
+	if completed && f.cancel != nil {
+		f.cancel()
+	}
+	return completed
+}
+
+// Promise is the writable side of a Future, completed explicitly by Resolve or Reject.
+type Promise[T any] struct {
+	future *Future[T]
+}
+
+// NewPromise creates a Promise with an uncompleted Future.
+func NewPromise[T any]() *Promise[T] {
+	return &Promise[T]{future: newFuture[T](nil)}
+}
+
+// NewPromiseContext creates a Promise together with a context derived from ctx that is canceled
+// when its Future completes or is canceled, so the producer can stop early.
+func NewPromiseContext[T any](ctx context.Context) (*Promise[T], context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Promise[T]{future: newFuture[T](cancel)}, ctx
+}
+
+// Future returns the Future completed by this Promise.
+func (p *Promise[T]) Future() *Future[T] {
+	return p.future
+}
+
+// Resolve completes the Future with value. It returns false if the Future had already completed.
+func (p *Promise[T]) Resolve(value T) bool {
+	return p.future.complete(value, nil)
+}
+
+// Reject completes the Future with err. It returns false if the Future had already completed.
+func (p *Promise[T]) Reject(err error) bool {
+	var zero T
+	return p.future.complete(zero, err)
+}
+
+// Then returns a Future that, once f completes successfully, runs next with its value.
+// If f fails, the returned Future fails with the same error and next is not called.
+func Then[T any, R any](ctx context.Context, f *Future[T], next func(ctx context.Context, value T) (R, error)) *Future[R] {
+	return Go(ctx, func(ctx context.Context) (R, error) {
+		value, err := f.Get(ctx)
+		if err != nil {
+			var zero R
+			return zero, err
+		}
+		return next(ctx, value)
+	})
+}
+
+// Map returns a Future of mapper applied to the value of f.
+// If f fails, the returned Future fails with the same error.
+func Map[T any, R any](f *Future[T], mapper func(value T) R) *Future[R] {
+	return Then(context.Background(), f, func(_ context.Context, value T) (R, error) {
+		return mapper(value), nil
+	})
+}
+
+// AllOf returns a Future of the values of all fs in order.
+// It fails with the first error of any of fs without waiting for the rest.
+func AllOf[T any](fs ...*Future[T]) *Future[[]T] {
+	all := newFuture[[]T](nil)
+	values := make([]T, len(fs))
+	var wg sync.WaitGroup
+	wg.Add(len(fs))
+	for i, f := range fs {
+		go func() {
+			defer wg.Done()
+			select {
+			case <-f.Done():
+				if f.err != nil {
+					all.complete(nil, f.err)
+					return
+				}
+				values[i] = f.value
+			case <-all.Done():
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		all.complete(values, nil)
+	}()
+	return all
+}
+
+// AnyOf returns a Future completed with the result of whichever of fs completes first,
+// whether it succeeded or failed.
+func AnyOf[T any](fs ...*Future[T]) *Future[T] {
+	winner := newFuture[T](nil)
+	if len(fs) == 0 {
+		var zero T
+		winner.complete(zero, ErrNoFutures)
+		return winner
+	}
+	for _, f := range fs {
+		go func() {
+			select {
+			case <-f.Done():
+				winner.complete(f.value, f.err)
+			case <-winner.Done():
+			}
+		}()
+	}
+	return winner
+}
+
+// FirstSuccessful returns a Future completed with the value of whichever of fs succeeds first.
+// If all of fs fail, it fails with all of their errors joined.
+func FirstSuccessful[T any](fs ...*Future[T]) *Future[T] {
+	first := newFuture[T](nil)
+	if len(fs) == 0 {
+		var zero T
+		first.complete(zero, ErrNoFutures)
+		return first
+	}
+	errs := make([]error, len(fs))
+	var wg sync.WaitGroup
+	wg.Add(len(fs))
+	for i, f := range fs {
+		go func() {
+			defer wg.Done()
+			select {
+			case <-f.Done():
+				if f.err != nil {
+					errs[i] = f.err
+					return
+				}
+				first.complete(f.value, nil)
+			case <-first.Done():
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		var zero T
+		first.complete(zero, errors.Join(errs...))
+	}()
+	return first
+}
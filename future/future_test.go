@@ -0,0 +1,169 @@
+package future
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGo(t *testing.T) {
+	f := Go(context.Background(), func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+	value, err := f.Get(context.Background())
+	if err != nil || value != 42 {
+		t.Errorf("expected 42, got %v, %v", value, err)
+	}
+	select {
+	case <-f.Done():
+	default:
+		t.Error("expected Done to be closed")
+	}
+	if f.Cancel() {
+		t.Error("expected Cancel to fail on a completed future")
+	}
+}
+
+func TestGoError(t *testing.T) {
+	errBoom := errors.New("boom")
+	f := Go(context.Background(), func(ctx context.Context) (int, error) {
+		return 0, errBoom
+	})
+	if _, err := f.Get(context.Background()); !errors.Is(err, errBoom) {
+		t.Errorf("expected boom, got %v", err)
+	}
+}
+
+func TestGoPanic(t *testing.T) {
+	f := Go(context.Background(), func(ctx context.Context) (int, error) {
+		panic("oops")
+	})
+	if _, err := f.Get(context.Background()); err == nil || !strings.Contains(err.Error(), "oops") {
+		t.Errorf("expected panic error, got %v", err)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	canceled := make(chan struct{})
+	f := Go(context.Background(), func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+		close(canceled)
+		return 0, ctx.Err()
+	})
+	if !f.Cancel() {
+		t.Error("expected Cancel to succeed")
+	}
+	if _, err := f.Get(context.Background()); !errors.Is(err, ErrCanceled) {
+		t.Errorf("expected ErrCanceled, got %v", err)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Error("expected the computation to be canceled")
+	}
+}
+
+func TestGetContext(t *testing.T) {
+	p := NewPromise[int]()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := p.Future().Get(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestPromise(t *testing.T) {
+	p := NewPromise[string]()
+	go p.Resolve("ok")
+	if value, err := p.Future().Get(context.Background()); err != nil || value != "ok" {
+		t.Errorf("expected ok, got %v, %v", value, err)
+	}
+	if p.Reject(errors.New("late")) {
+		t.Error("expected Reject to fail on a completed future")
+	}
+}
+
+func TestThenAndMap(t *testing.T) {
+	f := Go(context.Background(), func(ctx context.Context) (int, error) {
+		return 2, nil
+	})
+	doubled := Then(context.Background(), f, func(ctx context.Context, v int) (int, error) {
+		return v * 2, nil
+	})
+	text := Map(doubled, func(v int) string {
+		return strings.Repeat("x", v)
+	})
+	if value, err := text.Get(context.Background()); err != nil || value != "xxxx" {
+		t.Errorf("expected xxxx, got %v, %v", value, err)
+	}
+
+	errBoom := errors.New("boom")
+	p := NewPromise[int]()
+	p.Reject(errBoom)
+	called := false
+	next := Then(context.Background(), p.Future(), func(ctx context.Context, v int) (int, error) {
+		called = true
+		return v, nil
+	})
+	if _, err := next.Get(context.Background()); !errors.Is(err, errBoom) || called {
+		t.Errorf("expected boom without calling next, got %v", err)
+	}
+}
+
+func TestAllOf(t *testing.T) {
+	ps := []*Promise[int]{NewPromise[int](), NewPromise[int](), NewPromise[int]()}
+	all := AllOf(ps[0].Future(), ps[1].Future(), ps[2].Future())
+	ps[2].Resolve(3)
+	ps[0].Resolve(1)
+	ps[1].Resolve(2)
+	values, err := all.Get(context.Background())
+	if err != nil || len(values) != 3 || values[0] != 1 || values[1] != 2 || values[2] != 3 {
+		t.Errorf("unexpected result %v, %v", values, err)
+	}
+
+	errBoom := errors.New("boom")
+	pending := NewPromise[int]()
+	failed := NewPromise[int]()
+	failed.Reject(errBoom)
+	if _, err := AllOf(pending.Future(), failed.Future()).Get(context.Background()); !errors.Is(err, errBoom) {
+		t.Errorf("expected boom, got %v", err)
+	}
+
+	if values, err := AllOf[int]().Get(context.Background()); err != nil || len(values) != 0 {
+		t.Errorf("expected empty result, got %v, %v", values, err)
+	}
+}
+
+func TestAnyOf(t *testing.T) {
+	pending := NewPromise[int]()
+	failed := NewPromise[int]()
+	errBoom := errors.New("boom")
+	failed.Reject(errBoom)
+	if _, err := AnyOf(pending.Future(), failed.Future()).Get(context.Background()); !errors.Is(err, errBoom) {
+		t.Errorf("expected boom, got %v", err)
+	}
+	if _, err := AnyOf[int]().Get(context.Background()); !errors.Is(err, ErrNoFutures) {
+		t.Errorf("expected ErrNoFutures, got %v", err)
+	}
+}
+
+func TestFirstSuccessful(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+	a, b, c := NewPromise[int](), NewPromise[int](), NewPromise[int]()
+	first := FirstSuccessful(a.Future(), b.Future(), c.Future())
+	a.Reject(errA)
+	c.Resolve(3)
+	if value, err := first.Get(context.Background()); err != nil || value != 3 {
+		t.Errorf("expected 3, got %v, %v", value, err)
+	}
+
+	a, b = NewPromise[int](), NewPromise[int]()
+	a.Reject(errA)
+	b.Reject(errB)
+	_, err := FirstSuccessful(a.Future(), b.Future()).Get(context.Background())
+	if !errors.Is(err, errA) || !errors.Is(err, errB) {
+		t.Errorf("expected joined errors, got %v", err)
+	}
+}
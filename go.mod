@@ -1,6 +1,6 @@
 module github.com/soyacen/goconc
 
-go 1.20
+go 1.23.0
 
 require (
 	github.com/petermattis/goid v0.0.0-20250904145737-900bdf8bb490
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/panjf2000/ants/v2"
+	"github.com/soyacen/goconc/gofer"
 )
 
 func TestGofer_Go(t *testing.T) {
@@ -77,3 +78,23 @@ func TestGofer_Close(t *testing.T) {
 func NewDefaultAntsPool() (*ants.Pool, error) {
 	return ants.NewPool(10)
 }
+
+func TestGofer_Submit(t *testing.T) {
+	// 创建ants池
+	pool, err := NewDefaultAntsPool()
+	if err != nil {
+		t.Fatalf("failed to create ants pool: %v", err)
+	}
+	defer pool.Release()
+
+	f, err := gofer.Submit(&Gofer{Pool: pool}, func(ctx context.Context) (string, error) {
+		return "done", nil
+	})
+	if err != nil {
+		t.Fatalf("Submit() returned unexpected error: %v", err)
+	}
+	value, err := f.Get(context.Background())
+	if err != nil || value != "done" {
+		t.Errorf("expected done, got %v, %v", value, err)
+	}
+}
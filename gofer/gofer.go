@@ -14,4 +14,55 @@ type Gofer interface {
 	// ctx: 上下文，用于控制关闭超时
 	// 返回错误信息，如果关闭过程中出现错误则返回具体错误
 	Close(ctx context.Context) error
-}
\ No newline at end of file
+}
+
+// ContextGofer 是支持上下文的 Gofer 扩展接口
+type ContextGofer interface {
+	Gofer
+
+	// GoContext 启动一个异步任务，任务队列已满时阻塞直到有空间或 ctx 结束
+	// ctx: 上下文，会被传给任务；任务在开始执行前 ctx 已经结束的，任务会被跳过
+	// f: 要执行的任务函数
+	// 返回错误信息，如果启动失败则返回具体错误，ctx 结束时返回 ctx 的错误
+	GoContext(ctx context.Context, f func(ctx context.Context)) error
+}
+
+// GoContext 在 g 中执行一个可感知上下文的任务
+// 如果 g 实现了 ContextGofer，调用其 GoContext；否则通过 g.Go 提交，任务开始执行前 ctx 已经结束的会被跳过，
+// 但提交本身不会因队列已满而阻塞
+func GoContext(ctx context.Context, g Gofer, f func(ctx context.Context)) error {
+	if cg, ok := g.(ContextGofer); ok {
+		return cg.GoContext(ctx, f)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return g.Go(func() {
+		if ctx.Err() != nil {
+			return
+		}
+		f(ctx)
+	})
+}
+
+// DiscardGofer 是能够通知任务被丢弃的 Gofer 扩展接口
+// 有些执行器在接受任务（Go 返回 nil）之后仍可能丢弃任务，例如 Sample 的 DiscardPolicy 和 DiscardOldestPolicy，
+// 等待任务结果的调用者需要通过 GoDiscard 得知任务不会被执行
+type DiscardGofer interface {
+	Gofer
+
+	// GoDiscard 启动一个异步任务
+	// f: 要执行的任务函数
+	// onDiscard: 任务被接受后又被丢弃、不会执行时调用，err 为丢弃的原因；为 nil 时忽略
+	// 返回错误信息，如果启动失败则返回具体错误，此时 onDiscard 不会被调用
+	GoDiscard(f func(), onDiscard func(err error)) error
+}
+
+// GoDiscard 在 g 中执行任务 f，任务被丢弃时调用 onDiscard
+// 如果 g 实现了 DiscardGofer，调用其 GoDiscard；否则通过 g.Go 提交，这类执行器不会丢弃已接受的任务
+func GoDiscard(g Gofer, f func(), onDiscard func(err error)) error {
+	if dg, ok := g.(DiscardGofer); ok {
+		return dg.GoDiscard(f, onDiscard)
+	}
+	return g.Go(f)
+}
@@ -1,6 +1,6 @@
 module github.com/soyacen/goconc/gofer/gopgpool
 
-go 1.20
+go 1.23.0
 
 require (
 	github.com/soyacen/goconc v0.0.0-00010101000000-000000000000
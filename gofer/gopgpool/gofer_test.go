@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/soyacen/goconc/gofer"
 	"gopkg.in/go-playground/pool.v3"
 )
 
@@ -66,6 +67,23 @@ func TestGofer_Close(t *testing.T) {
 	}
 }
 
+func TestGofer_Submit(t *testing.T) {
+	// 创建go-playground池
+	pool := newDefaultGoPgPool()
+	defer pool.Close()
+
+	f, err := gofer.Submit(&Gofer{Pool: pool}, func(ctx context.Context) (string, error) {
+		return "done", nil
+	})
+	if err != nil {
+		t.Fatalf("Submit() returned unexpected error: %v", err)
+	}
+	value, err := f.Get(context.Background())
+	if err != nil || value != "done" {
+		t.Errorf("expected done, got %v, %v", value, err)
+	}
+}
+
 // newDefaultGoPgPool 创建默认配置的go-playground池用于测试
 func newDefaultGoPgPool() pool.Pool {
 	return pool.NewLimited(10)
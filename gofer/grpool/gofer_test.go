@@ -0,0 +1,44 @@
+package grpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ivpusic/grpool"
+	"github.com/soyacen/goconc/gofer"
+)
+
+func TestGofer_Submit(t *testing.T) {
+	// 创建grpool池
+	pool := newDefaultGrPool()
+	defer pool.Release()
+
+	g := &Gofer{Pool: pool}
+	f, err := gofer.Submit(g, func(ctx context.Context) (string, error) {
+		return "done", nil
+	})
+	if err != nil {
+		t.Fatalf("Submit() returned unexpected error: %v", err)
+	}
+	value, err := f.Get(context.Background())
+	if err != nil || value != "done" {
+		t.Errorf("expected done, got %v, %v", value, err)
+	}
+
+	errBoom := errors.New("boom")
+	f, err = gofer.Submit(g, func(ctx context.Context) (string, error) {
+		return "", errBoom
+	})
+	if err != nil {
+		t.Fatalf("Submit() returned unexpected error: %v", err)
+	}
+	if _, err := f.Get(context.Background()); !errors.Is(err, errBoom) {
+		t.Errorf("expected %v, got %v", errBoom, err)
+	}
+}
+
+// newDefaultGrPool 创建默认配置的grpool池用于测试
+func newDefaultGrPool() *grpool.Pool {
+	return grpool.NewPool(2, 10)
+}
@@ -0,0 +1,81 @@
+package sample
+
+import (
+	"time"
+)
+
+// AutoscaleConfig 配置自动伸缩器，自动伸缩器每隔 Interval 根据任务队列深度和任务的平均等待时长调整核心线程数
+//   - 队列深度大于 ScaleUpQueueDepth，或设置了 ScaleUpLatency 且平均等待时长大于 ScaleUpLatency 时，核心线程数增加 Step
+//   - 队列为空、至少有 Step 个核心线程空闲，且平均等待时长不大于 ScaleUpLatency 时，核心线程数减少 Step
+type AutoscaleConfig struct {
+	// Interval 调整的间隔，默认为 1 秒
+	Interval time.Duration
+	// MinCorePoolSize 核心线程数的下限，默认为 1
+	MinCorePoolSize int
+	// MaxCorePoolSize 核心线程数的上限，默认为 MaximumPoolSize，且始终不超过当前的 MaximumPoolSize
+	MaxCorePoolSize int
+	// Step 每次调整的线程数，默认为 1
+	Step int
+	// ScaleUpQueueDepth 触发扩容的队列深度
+	ScaleUpQueueDepth int
+	// ScaleUpLatency 触发扩容的任务平均等待时长，0 表示不根据等待时长扩容
+	ScaleUpLatency time.Duration
+}
+
+func (c *AutoscaleConfig) correct(o *options) {
+	if c.Interval <= 0 {
+		c.Interval = time.Second
+	}
+	if c.MinCorePoolSize <= 0 {
+		c.MinCorePoolSize = 1
+	}
+	if c.MaxCorePoolSize <= 0 {
+		c.MaxCorePoolSize = o.MaximumPoolSize
+	}
+	if c.MaxCorePoolSize < c.MinCorePoolSize {
+		c.MaxCorePoolSize = c.MinCorePoolSize
+	}
+	if c.Step <= 0 {
+		c.Step = 1
+	}
+	if c.ScaleUpQueueDepth < 0 {
+		c.ScaleUpQueueDepth = 0
+	}
+}
+
+// autoscale 每隔 config.Interval 调整一次核心线程数，直到池被关闭
+func (g *Gofer) autoscale(config *AutoscaleConfig) {
+	ticker := time.NewTicker(config.Interval)
+	defer ticker.Stop()
+	started, waitTime := g.started.Load(), g.waitTime.Load()
+	for {
+		select {
+		case <-g.done:
+			return
+		case <-ticker.C:
+			lastStarted, lastWaitTime := started, waitTime
+			started, waitTime = g.started.Load(), g.waitTime.Load()
+			var latency time.Duration
+			if count := started - lastStarted; count > 0 {
+				latency = max(time.Duration(waitTime-lastWaitTime)/time.Duration(count), 0)
+			}
+			g.scale(config, latency)
+		}
+	}
+}
+
+// scale 根据当前的队列深度和上一个间隔内任务的平均等待时长 latency 调整核心线程数
+func (g *Gofer) scale(config *AutoscaleConfig, latency time.Duration) {
+	depth := g.WorkQueue.Len()
+	core := g.CorePoolSize()
+	size := core
+	switch {
+	case depth > config.ScaleUpQueueDepth || (config.ScaleUpLatency > 0 && latency > config.ScaleUpLatency):
+		size = max(core, min(core+config.Step, config.MaxCorePoolSize, g.MaximumPoolSize()))
+	case depth == 0 && int(g.active.Load()) <= core-config.Step && (config.ScaleUpLatency <= 0 || latency <= config.ScaleUpLatency):
+		size = min(core, max(core-config.Step, config.MinCorePoolSize))
+	}
+	if size != core {
+		g.SetCorePoolSize(size)
+	}
+}
@@ -0,0 +1,121 @@
+package sample_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/soyacen/goconc/gofer/sample"
+)
+
+// waitFor 等待 cond 成立，超时返回 false
+func waitFor(cond func() bool) bool {
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return true
+}
+
+// TestSetPoolSize 测试运行时修改线程数和空闲存活时间
+func TestSetPoolSize(t *testing.T) {
+	g := sample.New(
+		sample.CorePoolSize(1),
+		sample.MaximumPoolSize(4),
+		sample.KeepAliveTime(time.Minute),
+	)
+	if err := g.SetCorePoolSize(0); !errors.Is(err, sample.ErrInvalidPoolSize) {
+		t.Errorf("Expected ErrInvalidPoolSize, got: %v", err)
+	}
+	if err := g.SetCorePoolSize(5); !errors.Is(err, sample.ErrInvalidPoolSize) {
+		t.Errorf("Expected ErrInvalidPoolSize, got: %v", err)
+	}
+	if err := g.SetMaximumPoolSize(0); !errors.Is(err, sample.ErrInvalidPoolSize) {
+		t.Errorf("Expected ErrInvalidPoolSize, got: %v", err)
+	}
+	if err := g.SetKeepAliveTime(0); !errors.Is(err, sample.ErrInvalidKeepAliveTime) {
+		t.Errorf("Expected ErrInvalidKeepAliveTime, got: %v", err)
+	}
+	if err := g.SetKeepAliveTime(time.Second); err != nil || g.KeepAliveTime() != time.Second {
+		t.Errorf("Unexpected keep alive time %v, error: %v", g.KeepAliveTime(), err)
+	}
+
+	// 占满全部线程，使后续任务进入队列
+	release := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		g.Go(func() { <-release })
+	}
+	for i := 0; i < 2; i++ {
+		g.Go(func() { <-release })
+	}
+	if g.WorkQueue.Len() != 2 {
+		t.Fatalf("Expected 2 queued tasks, got %d", g.WorkQueue.Len())
+	}
+	// 增大核心线程数后立即启动新线程执行队列中的任务
+	if err := g.SetMaximumPoolSize(8); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := g.SetCorePoolSize(6); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if g.CorePoolSize() != 6 || g.MaximumPoolSize() != 8 {
+		t.Errorf("Unexpected pool sizes %d, %d", g.CorePoolSize(), g.MaximumPoolSize())
+	}
+	if !waitFor(func() bool { return g.WorkQueue.Len() == 0 }) {
+		t.Errorf("Expected queued tasks to start, queue length %d", g.WorkQueue.Len())
+	}
+
+	// 缩小后，多余的线程在执行完当前任务后退出
+	if err := g.SetCorePoolSize(1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := g.SetMaximumPoolSize(1); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	close(release)
+	if !waitFor(func() bool { return g.PoolSize() == 1 }) {
+		t.Errorf("Expected pool to shrink to 1, got %d", g.PoolSize())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := g.Close(ctx); err != nil {
+		t.Errorf("Unexpected error closing pool: %v", err)
+	}
+}
+
+// TestAutoscale 测试自动伸缩器根据队列深度扩容并在空闲后缩容
+func TestAutoscale(t *testing.T) {
+	g := sample.New(
+		sample.CorePoolSize(1),
+		sample.MaximumPoolSize(4),
+		sample.Autoscale(sample.AutoscaleConfig{
+			Interval:        10 * time.Millisecond,
+			MaxCorePoolSize: 4,
+		}),
+	)
+	release := make(chan struct{})
+	// 核心线程和非核心线程都被占满后，任务进入队列
+	for i := 0; i < 8; i++ {
+		if err := g.Go(func() { <-release }); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if !waitFor(func() bool { return g.CorePoolSize() == 4 }) {
+		t.Errorf("Expected core pool size to grow to 4, got %d", g.CorePoolSize())
+	}
+	close(release)
+	if !waitFor(func() bool { return g.CorePoolSize() == 1 }) {
+		t.Errorf("Expected core pool size to shrink to 1, got %d", g.CorePoolSize())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := g.Close(ctx); err != nil {
+		t.Errorf("Unexpected error closing pool: %v", err)
+	}
+}
@@ -18,9 +18,18 @@ var (
 	ErrPoolFull   = errors.New("gofer: pool is full")
 	ErrPoolClosed = errors.New("gofer: pool is closed")
 	ErrTaskNil    = errors.New("gofer: task is nil")
+	// ErrUnsupported 任务队列不支持 GoPriority 或 GoDelay
+	ErrUnsupported = errors.New("gofer: operation is not supported by the work queue")
+
+	ErrInvalidPoolSize      = errors.New("gofer: invalid pool size")
+	ErrInvalidKeepAliveTime = errors.New("gofer: invalid keep alive time")
 )
 
-var _ gofer.Gofer = (*Gofer)(nil)
+var (
+	_ gofer.ContextGofer = (*Gofer)(nil)
+	_ gofer.StatsGofer   = (*Gofer)(nil)
+	_ gofer.DiscardGofer = (*Gofer)(nil)
+)
 
 type options struct {
 	CorePoolSize int
@@ -31,7 +40,15 @@ type options struct {
 
 	WorkQueueSize int
 
+	WorkQueue Queue
+
 	Recover func(p any, stack []byte)
+
+	Rejection RejectionPolicy
+
+	Autoscale *AutoscaleConfig
+
+	Hooks gofer.Hooks
 }
 
 type Option func(*options)
@@ -60,12 +77,40 @@ func WorkQueueSize(size int) Option {
 	}
 }
 
+// WorkQueue 设置任务队列，默认为容量为 WorkQueueSize 的 FIFO 队列
+func WorkQueue(queue Queue) Option {
+	return func(o *options) {
+		o.WorkQueue = queue
+	}
+}
+
 func Recover(f func(p any, stack []byte)) Option {
 	return func(o *options) {
 		o.Recover = f
 	}
 }
 
+// Rejection 设置任务队列已满时 Go 的拒绝策略，默认为 AbortPolicy
+func Rejection(policy RejectionPolicy) Option {
+	return func(o *options) {
+		o.Rejection = policy
+	}
+}
+
+// Autoscale 启用自动伸缩器，根据任务队列深度和任务等待时长定期调整核心线程数
+func Autoscale(config AutoscaleConfig) Option {
+	return func(o *options) {
+		o.Autoscale = &config
+	}
+}
+
+// Hooks 设置生命周期回调，OnPanic 在 Recover 之后调用，OnReject 只在提交返回错误时调用
+func Hooks(hooks gofer.Hooks) Option {
+	return func(o *options) {
+		o.Hooks = hooks
+	}
+}
+
 func (o *options) Apply(opts ...Option) *options {
 	for _, opt := range opts {
 		opt(o)
@@ -83,25 +128,39 @@ func (o *options) Correct() *options {
 	if o.KeepAliveTime <= 0 {
 		o.KeepAliveTime = 5 * time.Minute
 	}
-	if o.WorkQueueSize < 0 {
+	if o.WorkQueueSize <= 0 {
 		o.WorkQueueSize = runtime.NumCPU() * o.MaximumPoolSize
 	}
+	if o.WorkQueue == nil {
+		o.WorkQueue = NewFIFOQueue(o.WorkQueueSize)
+	}
 	if o.Recover == nil {
 		o.Recover = func(p any, stack []byte) {
 			fmt.Printf("gofer: panic trigger, %v, stack: %s", p, stack)
 		}
 	}
+	if o.Rejection == nil {
+		o.Rejection = AbortPolicy
+	}
+	if o.Autoscale != nil {
+		o.Autoscale.correct(o)
+	}
 	return o
 }
 
 func New(opts ...Option) *Gofer {
 	options := new(options).Apply(opts...).Correct()
-	return &Gofer{
+	g := &Gofer{
 		options:     options,
 		coreWorkers: make(map[*coreWorker]struct{}, options.CorePoolSize),
 		edgeWorkers: make(map[*edgeWorker]struct{}, options.MaximumPoolSize-options.CorePoolSize),
-		WorkQueue:   make(chan func(), options.WorkQueueSize),
+		WorkQueue:   options.WorkQueue,
+		done:        make(chan struct{}),
+	}
+	if options.Autoscale != nil {
+		go g.autoscale(options.Autoscale)
 	}
+	return g
 }
 
 type Gofer struct {
@@ -110,45 +169,218 @@ type Gofer struct {
 	wg          sync.WaitGroup
 	coreWorkers map[*coreWorker]struct{}
 	edgeWorkers map[*edgeWorker]struct{}
-	WorkQueue   chan func()
+	WorkQueue   Queue
 	closed      atomic.Bool
+	// done 在关闭时被关闭，用于停止自动伸缩器
+	done chan struct{}
+	// active 正在执行任务的工作线程数
+	active atomic.Int64
+	// started 开始执行的任务总数
+	started   atomic.Uint64
+	submitted atomic.Uint64
+	completed atomic.Uint64
+	failed    atomic.Uint64
+	rejected  atomic.Uint64
+	discarded atomic.Uint64
+	waitTime  atomic.Int64
+	runTime   atomic.Int64
 }
 
 func (g *Gofer) Go(task func()) error {
 	if task == nil {
 		return ErrTaskNil
 	}
+	return g.submit(context.Background(), g.newTask(task), false)
+}
+
+// GoDiscard 与 Go 相同，任务被拒绝策略丢弃时调用 onDiscard
+func (g *Gofer) GoDiscard(task func(), onDiscard func(err error)) error {
+	if task == nil {
+		return ErrTaskNil
+	}
+	t := g.newTask(task)
+	t.OnDiscard = onDiscard
+	return g.submit(context.Background(), t, false)
+}
+
+// Discard 记录被丢弃的任务，并通过 task.OnDiscard 通知任务不会被执行
+// 自定义的拒绝策略丢弃任务时应调用 Discard
+func (g *Gofer) Discard(task *Task, err error) {
+	g.discarded.Add(1)
+	if task.OnDiscard != nil {
+		task.OnDiscard(err)
+	}
+}
+
+// GoPriority 提交一个带优先级的任务，priority 越大越先执行。任务队列没有实现 PriorityQueue 时返回 ErrUnsupported。
+func (g *Gofer) GoPriority(task func(), priority int) error {
+	if task == nil {
+		return ErrTaskNil
+	}
+	if _, ok := g.WorkQueue.(PriorityQueue); !ok {
+		return ErrUnsupported
+	}
+	t := g.newTask(task)
+	t.Priority = priority
+	return g.submit(context.Background(), t, false)
+}
+
+// GoDelay 提交一个延迟任务，任务在 delay 之后才会被执行。任务队列没有实现 DelayQueue 时返回 ErrUnsupported。
+func (g *Gofer) GoDelay(task func(), delay time.Duration) error {
+	if task == nil {
+		return ErrTaskNil
+	}
+	if _, ok := g.WorkQueue.(DelayQueue); !ok {
+		return ErrUnsupported
+	}
+	t := g.newTask(task)
+	t.At = time.Now().Add(delay)
+	return g.submit(context.Background(), t, false)
+}
+
+// GoContext 提交一个可感知上下文的任务，任务队列已满时阻塞直到有空间、ctx 结束或池被关闭。
+// ctx 会被传给任务；任务在队列中等待期间 ctx 已经结束的，任务会被跳过并计入 Discarded。拒绝策略不适用于 GoContext。
+func (g *Gofer) GoContext(ctx context.Context, task func(ctx context.Context)) error {
+	if task == nil {
+		return ErrTaskNil
+	}
+	if err := ctx.Err(); err != nil {
+		return g.reject(err)
+	}
+	t := g.newTask(func() {
+		task(ctx)
+	})
+	run := t.Run
+	t.Run = func() {
+		if ctx.Err() != nil {
+			g.discarded.Add(1)
+			return
+		}
+		run()
+	}
+	return g.submit(ctx, t, true)
+}
+
+// newTask 创建任务，任务的 Run 会捕获 panic、记录统计数据并调用回调
+func (g *Gofer) newTask(f func()) *Task {
+	t := &Task{}
+	t.Run = func() {
+		g.execute(t, f)
+	}
+	return t
+}
+
+// execute 执行任务 t 的函数 f
+func (g *Gofer) execute(t *Task, f func()) {
+	hooks := g.options.Hooks
+	ready := t.submitted
+	if t.At.After(ready) {
+		ready = t.At
+	}
+	// 任务可能在 At 之前被执行，例如没有经过 DelayQueue 的延迟任务，等待时长不小于 0
+	wait := max(time.Since(ready), 0)
+	g.waitTime.Add(int64(wait))
+	g.started.Add(1)
+	if hooks.BeforeExecute != nil {
+		hooks.BeforeExecute(wait)
+	}
+	g.active.Add(1)
+	start := time.Now()
+	panicked := false
+	defer func() {
+		run := time.Since(start)
+		g.runTime.Add(int64(run))
+		g.active.Add(-1)
+		if panicked {
+			g.failed.Add(1)
+		} else {
+			g.completed.Add(1)
+		}
+		if hooks.AfterExecute != nil {
+			hooks.AfterExecute(run, panicked)
+		}
+	}()
+	defer func() {
+		if p := recover(); p != nil {
+			panicked = true
+			stack := debug.Stack()
+			g.options.Recover(p, stack)
+			if hooks.OnPanic != nil {
+				hooks.OnPanic(p, stack)
+			}
+		}
+	}()
+	f()
+}
+
+// reject 记录提交失败的任务并调用回调
+func (g *Gofer) reject(err error) error {
+	g.rejected.Add(1)
+	if g.options.Hooks.OnReject != nil {
+		g.options.Hooks.OnReject(err)
+	}
+	return err
+}
+
+// submit 提交 task，新建的工作线程直接执行已到期的 task，否则将 task 放入任务队列。
+// 任务队列已满时，block 为 false 使用拒绝策略处理，否则阻塞等待。
+func (g *Gofer) submit(ctx context.Context, task *Task, block bool) (err error) {
+	defer func() {
+		if err == nil {
+			g.submitted.Add(1)
+		}
+	}()
 	if g.closed.Load() {
-		return ErrPoolClosed
+		return g.reject(ErrPoolClosed)
 	}
 	g.m.Lock()
-	defer g.m.Unlock()
 	if g.closed.Load() {
-		return ErrPoolClosed
+		g.m.Unlock()
+		return g.reject(ErrPoolClosed)
 	}
+	task.submitted = time.Now()
+	due := !task.At.After(task.submitted)
 	if len(g.coreWorkers) < g.options.CorePoolSize {
 		worker := &coreWorker{Gofer: g}
 		g.coreWorkers[worker] = struct{}{}
-		worker.work()
-	} else if len(g.edgeWorkers) < g.options.MaximumPoolSize-g.options.CorePoolSize {
+		if due {
+			worker.work(task)
+			g.m.Unlock()
+			return nil
+		}
+		// 延迟任务需要经过任务队列，只启动工作线程
+		worker.work(nil)
+	} else if due && len(g.edgeWorkers) < g.options.MaximumPoolSize-g.options.CorePoolSize {
 		worker := &edgeWorker{Gofer: g}
 		g.edgeWorkers[worker] = struct{}{}
-		worker.work()
+		worker.work(task)
+		g.m.Unlock()
+		return nil
 	}
-	job := func() {
-		defer func() {
-			if p := recover(); p != nil {
-				g.options.Recover(p, debug.Stack())
-			}
-		}()
-		task()
+	if g.WorkQueue.Offer(task) {
+		g.m.Unlock()
+		return nil
 	}
-	select {
-	case g.WorkQueue <- job:
+	g.m.Unlock()
+	if !block {
+		if err := g.options.Rejection(g, task); err != nil {
+			return g.reject(err)
+		}
 		return nil
-	default:
-		return ErrPoolFull
 	}
+	if err := g.put(ctx, task); err != nil {
+		return g.reject(err)
+	}
+	return nil
+}
+
+// put 将 task 放入任务队列，队列已满时阻塞直到有空间、ctx 结束或池被关闭
+func (g *Gofer) put(ctx context.Context, task *Task) error {
+	err := g.WorkQueue.Put(ctx, task)
+	if errors.Is(err, ErrQueueClosed) {
+		return ErrPoolClosed
+	}
+	return err
 }
 
 func (g *Gofer) Close(ctx context.Context) error {
@@ -161,7 +393,8 @@ func (g *Gofer) Close(ctx context.Context) error {
 		return ErrPoolClosed
 	}
 	g.closed.Store(true)
-	close(g.WorkQueue)
+	close(g.done)
+	g.WorkQueue.Close()
 	g.m.Unlock()
 	select {
 	case <-ctx.Done():
@@ -171,44 +404,190 @@ func (g *Gofer) Close(ctx context.Context) error {
 	}
 }
 
+// CorePoolSize 返回当前的核心线程数
+func (g *Gofer) CorePoolSize() int {
+	g.m.Lock()
+	defer g.m.Unlock()
+	return g.options.CorePoolSize
+}
+
+// MaximumPoolSize 返回当前的最大线程数
+func (g *Gofer) MaximumPoolSize() int {
+	g.m.Lock()
+	defer g.m.Unlock()
+	return g.options.MaximumPoolSize
+}
+
+// KeepAliveTime 返回当前非核心线程的空闲存活时间
+func (g *Gofer) KeepAliveTime() time.Duration {
+	g.m.Lock()
+	defer g.m.Unlock()
+	return g.options.KeepAliveTime
+}
+
+// PoolSize 返回当前的工作线程数
+func (g *Gofer) PoolSize() int {
+	g.m.Lock()
+	defer g.m.Unlock()
+	return len(g.coreWorkers) + len(g.edgeWorkers)
+}
+
+// SetCorePoolSize 修改核心线程数，size 必须大于 0 且不超过最大线程数。
+// 增大时，如果任务队列中有等待的任务，立即启动新的核心线程；
+// 减小时，多余的核心线程在执行完当前任务或空闲 KeepAliveTime 后退出。
+func (g *Gofer) SetCorePoolSize(size int) error {
+	g.m.Lock()
+	defer g.m.Unlock()
+	if size <= 0 || size > g.options.MaximumPoolSize {
+		return ErrInvalidPoolSize
+	}
+	g.options.CorePoolSize = size
+	if g.closed.Load() {
+		return nil
+	}
+	for n := min(size-len(g.coreWorkers), g.WorkQueue.Len()); n > 0; n-- {
+		worker := &coreWorker{Gofer: g}
+		g.coreWorkers[worker] = struct{}{}
+		worker.work(nil)
+	}
+	return nil
+}
+
+// SetMaximumPoolSize 修改最大线程数，size 不能小于核心线程数。
+// 减小时，多余的非核心线程在执行完当前任务后退出。
+func (g *Gofer) SetMaximumPoolSize(size int) error {
+	g.m.Lock()
+	defer g.m.Unlock()
+	if size < g.options.CorePoolSize {
+		return ErrInvalidPoolSize
+	}
+	g.options.MaximumPoolSize = size
+	return nil
+}
+
+// SetKeepAliveTime 修改非核心线程的空闲存活时间，d 必须大于 0，在工作线程下一次等待任务时生效。
+func (g *Gofer) SetKeepAliveTime(d time.Duration) error {
+	if d <= 0 {
+		return ErrInvalidKeepAliveTime
+	}
+	g.m.Lock()
+	defer g.m.Unlock()
+	g.options.KeepAliveTime = d
+	return nil
+}
+
+// Stats 返回当前的统计快照。
+// 只有提交返回错误的任务计入 Rejected，拒绝策略接受的任务计入 Submitted，其中被丢弃的任务同时计入 Discarded。
+// 通过 GoContext 提交、开始执行前 ctx 已经结束而被跳过的任务同样计入 Discarded。
+func (g *Gofer) Stats() gofer.Stats {
+	g.m.Lock()
+	core, edge := len(g.coreWorkers), len(g.edgeWorkers)
+	g.m.Unlock()
+	return gofer.Stats{
+		CoreWorkers:   core,
+		EdgeWorkers:   edge,
+		ActiveWorkers: int(g.active.Load()),
+		QueueDepth:    g.WorkQueue.Len(),
+		Submitted:     g.submitted.Load(),
+		Completed:     g.completed.Load(),
+		Failed:        g.failed.Load(),
+		Rejected:      g.rejected.Load(),
+		Discarded:     g.discarded.Load(),
+		WaitTime:      time.Duration(g.waitTime.Load()),
+		RunTime:       time.Duration(g.runTime.Load()),
+	}
+}
+
+// take 等待下一个任务，最多等待 KeepAliveTime
+func (g *Gofer) take() (*Task, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), g.KeepAliveTime())
+	defer cancel()
+	return g.WorkQueue.Take(ctx)
+}
+
+// workerStart 在工作线程启动时调用回调
+func (g *Gofer) workerStart() {
+	if g.options.Hooks.OnWorkerStart != nil {
+		g.options.Hooks.OnWorkerStart()
+	}
+}
+
+// workerExit 在工作线程退出时调用回调
+func (g *Gofer) workerExit() {
+	if g.options.Hooks.OnWorkerExit != nil {
+		g.options.Hooks.OnWorkerExit()
+	}
+}
+
 type coreWorker struct {
 	Gofer *Gofer
 }
 
-func (w *coreWorker) work() {
+func (w *coreWorker) work(first *Task) {
 	w.Gofer.wg.Add(1)
 	go func() {
 		defer w.Gofer.wg.Done()
-		for job := range w.Gofer.WorkQueue {
-			job()
+		w.Gofer.workerStart()
+		defer w.Gofer.workerExit()
+		if first != nil {
+			first.Run()
+		}
+		for !w.retire() {
+			task, err := w.Gofer.take()
+			if errors.Is(err, context.DeadlineExceeded) {
+				continue
+			}
+			if err != nil {
+				return
+			}
+			task.Run()
 		}
 	}()
 }
 
+// retire 在核心线程数超过 CorePoolSize 时移除当前线程，返回是否需要退出
+func (w *coreWorker) retire() bool {
+	w.Gofer.m.Lock()
+	defer w.Gofer.m.Unlock()
+	if len(w.Gofer.coreWorkers) <= w.Gofer.options.CorePoolSize {
+		return false
+	}
+	delete(w.Gofer.coreWorkers, w)
+	return true
+}
+
 type edgeWorker struct {
 	Gofer *Gofer
 }
 
-func (w *edgeWorker) work() {
+func (w *edgeWorker) work(first *Task) {
 	w.Gofer.wg.Add(1)
 	go func() {
 		defer w.Gofer.wg.Done()
-		ticker := time.NewTicker(w.Gofer.options.KeepAliveTime)
-		defer ticker.Stop()
-		for {
-			select {
-			case job, ok := <-w.Gofer.WorkQueue:
-				if !ok {
-					return
-				}
-				job()
-				ticker.Reset(w.Gofer.options.KeepAliveTime)
-			case <-ticker.C:
-				w.Gofer.m.Lock()
-				delete(w.Gofer.edgeWorkers, w)
-				w.Gofer.m.Unlock()
+		w.Gofer.workerStart()
+		defer w.Gofer.workerExit()
+		first.Run()
+		for !w.retire(false) {
+			task, err := w.Gofer.take()
+			if errors.Is(err, context.DeadlineExceeded) {
+				w.retire(true)
 				return
 			}
+			if err != nil {
+				return
+			}
+			task.Run()
 		}
 	}()
 }
+
+// retire 在 idle 为 true 或工作线程数超过 MaximumPoolSize 时移除当前线程，返回是否需要退出
+func (w *edgeWorker) retire(idle bool) bool {
+	w.Gofer.m.Lock()
+	defer w.Gofer.m.Unlock()
+	if !idle && len(w.Gofer.coreWorkers)+len(w.Gofer.edgeWorkers) <= w.Gofer.options.MaximumPoolSize {
+		return false
+	}
+	delete(w.Gofer.edgeWorkers, w)
+	return true
+}
@@ -62,7 +62,7 @@ func TestGoWithFullPool(t *testing.T) {
 	g := sample.New(
 		sample.CorePoolSize(1),
 		sample.MaximumPoolSize(1),
-		sample.WorkQueue(make(chan func(), 1)),
+		sample.WorkQueueSize(1),
 	)
 
 	// 占满池
@@ -323,6 +323,129 @@ func TestCoreAndNonCoreWorkers(t *testing.T) {
 	}
 }
 
+// TestGoContextBlocksUntilSpace 测试队列已满时GoContext阻塞直到有空间
+func TestGoContextBlocksUntilSpace(t *testing.T) {
+	g := sample.New(
+		sample.CorePoolSize(1),
+		sample.MaximumPoolSize(1),
+		sample.WorkQueueSize(1),
+	)
+	release := make(chan struct{})
+	// 占满工作线程和队列
+	if err := g.Go(func() { <-release }); err != nil {
+		t.Fatalf("Unexpected error submitting first task: %v", err)
+	}
+	if err := g.Go(func() {}); err != nil {
+		t.Fatalf("Unexpected error submitting second task: %v", err)
+	}
+
+	executed := make(chan struct{})
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+	err := g.GoContext(context.Background(), func(ctx context.Context) {
+		close(executed)
+	})
+	if err != nil {
+		t.Errorf("Unexpected error submitting blocked task: %v", err)
+	}
+	select {
+	case <-executed:
+	case <-time.After(time.Second):
+		t.Error("task was not executed within timeout")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := g.Close(ctx); err != nil {
+		t.Errorf("Unexpected error closing pool: %v", err)
+	}
+}
+
+// TestGoContextDeadline 测试队列已满且ctx超时时GoContext返回ctx的错误
+func TestGoContextDeadline(t *testing.T) {
+	g := sample.New(
+		sample.CorePoolSize(1),
+		sample.MaximumPoolSize(1),
+		sample.WorkQueueSize(1),
+	)
+	release := make(chan struct{})
+	defer close(release)
+	g.Go(func() { <-release })
+	g.Go(func() {})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := g.GoContext(ctx, func(ctx context.Context) {})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got: %v", err)
+	}
+}
+
+// TestGoContextSkipExpired 测试在队列中等待期间ctx结束的任务被跳过
+func TestGoContextSkipExpired(t *testing.T) {
+	g := sample.New(
+		sample.CorePoolSize(1),
+		sample.MaximumPoolSize(1),
+		sample.WorkQueueSize(1),
+	)
+	release := make(chan struct{})
+	g.Go(func() { <-release })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	executed := false
+	err := g.GoContext(ctx, func(ctx context.Context) {
+		executed = true
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error submitting task: %v", err)
+	}
+	cancel()
+	close(release)
+
+	closeCtx, closeCancel := context.WithTimeout(context.Background(), time.Second)
+	defer closeCancel()
+	if err := g.Close(closeCtx); err != nil {
+		t.Errorf("Unexpected error closing pool: %v", err)
+	}
+	if executed {
+		t.Error("Expected expired task to be skipped")
+	}
+}
+
+// TestGoContextClose 测试关闭池时唤醒阻塞的提交者
+func TestGoContextClose(t *testing.T) {
+	g := sample.New(
+		sample.CorePoolSize(1),
+		sample.MaximumPoolSize(1),
+		sample.WorkQueueSize(1),
+	)
+	release := make(chan struct{})
+	g.Go(func() { <-release })
+	g.Go(func() {})
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- g.GoContext(context.Background(), func(ctx context.Context) {})
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	closeErr := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		closeErr <- g.Close(ctx)
+	}()
+	if err := <-errC; !errors.Is(err, sample.ErrPoolClosed) {
+		t.Errorf("Expected ErrPoolClosed, got: %v", err)
+	}
+	close(release)
+	if err := <-closeErr; err != nil {
+		t.Errorf("Unexpected error closing pool: %v", err)
+	}
+}
+
 // BenchmarkGo 测试Go方法的性能
 func BenchmarkGo(b *testing.B) {
 	g := sample.New()
@@ -0,0 +1,341 @@
+package sample
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrQueueClosed = errors.New("gofer: queue is closed")
+
+// Task 是任务队列中的任务
+type Task struct {
+	// Run 任务函数
+	Run func()
+	// Priority 任务的优先级，值越大越先执行，只对 PriorityQueue 生效
+	Priority int
+	// At 任务最早的执行时间，只对 DelayQueue 生效
+	At time.Time
+	// OnDiscard 任务被拒绝策略丢弃、不会执行时调用，err 为丢弃的原因，为 nil 时忽略
+	OnDiscard func(err error)
+
+	// seq 任务放入队列的顺序，用于相同优先级或相同执行时间的任务保持先进先出
+	seq uint64
+	// submitted 任务被提交的时间，用于统计任务的等待时长
+	submitted time.Time
+}
+
+// Queue 是线程池的任务队列，实现必须是并发安全的
+type Queue interface {
+	// Offer 将任务放入队列，队列已满或已关闭时立即返回 false
+	Offer(task *Task) bool
+	// Put 将任务放入队列，队列已满时阻塞直到有空间或 ctx 结束，队列已关闭时返回 ErrQueueClosed
+	Put(ctx context.Context, task *Task) error
+	// Take 取出下一个可执行的任务，没有时阻塞直到有可执行的任务或 ctx 结束，队列已关闭且为空时返回 ErrQueueClosed
+	Take(ctx context.Context) (*Task, error)
+	// Poll 立即取出下一个可执行的任务，没有时返回 false
+	Poll() (*Task, bool)
+	// Len 返回队列中的任务数量
+	Len() int
+	// Close 关闭队列，之后不能再放入任务，已有的任务仍然可以被取出
+	Close()
+}
+
+// PriorityQueue 是按 Task.Priority 出队的任务队列，GoPriority 要求任务队列实现该接口
+type PriorityQueue interface {
+	Queue
+	// Prioritized 标记队列按 Task.Priority 从大到小出队
+	Prioritized()
+}
+
+// DelayQueue 是按 Task.At 延迟出队的任务队列，GoDelay 要求任务队列实现该接口
+type DelayQueue interface {
+	Queue
+	// Delayed 标记队列中的任务在 Task.At 之后才能被取出
+	Delayed()
+}
+
+// NewFIFOQueue 创建一个先进先出的任务队列，capacity 小于等于 0 时队列无界
+func NewFIFOQueue(capacity int) Queue {
+	return newBlockingQueue(&fifoStore{}, capacity, false)
+}
+
+// NewLIFOQueue 创建一个后进先出的任务队列，capacity 小于等于 0 时队列无界
+func NewLIFOQueue(capacity int) Queue {
+	return newBlockingQueue(&lifoStore{}, capacity, false)
+}
+
+// NewPriorityQueue 创建一个按 Task.Priority 从大到小出队的任务队列，相同优先级的任务先进先出，capacity 小于等于 0 时队列无界
+func NewPriorityQueue(capacity int) Queue {
+	return priorityQueue{newBlockingQueue(&heapStore{less: func(a, b *Task) bool {
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		return a.seq < b.seq
+	}}, capacity, false)}
+}
+
+// NewDelayQueue 创建一个延迟任务队列，任务在 Task.At 之后才能被取出，capacity 小于等于 0 时队列无界
+func NewDelayQueue(capacity int) Queue {
+	return delayQueue{newBlockingQueue(&heapStore{less: func(a, b *Task) bool {
+		if !a.At.Equal(b.At) {
+			return a.At.Before(b.At)
+		}
+		return a.seq < b.seq
+	}}, capacity, true)}
+}
+
+type priorityQueue struct {
+	*blockingQueue
+}
+
+func (priorityQueue) Prioritized() {}
+
+type delayQueue struct {
+	*blockingQueue
+}
+
+func (delayQueue) Delayed() {}
+
+// store 保存队列中的任务，决定任务的出队顺序
+type store interface {
+	push(task *Task)
+	peek() *Task
+	pop() *Task
+	len() int
+}
+
+// blockingQueue 在 store 之上实现了有界、可阻塞的 Queue
+type blockingQueue struct {
+	mu       sync.Mutex
+	store    store
+	capacity int
+	// delay 为 true 时，只有到达 Task.At 的任务才能被取出
+	delay  bool
+	seq    uint64
+	closed bool
+	// notEmpty 在放入任务或关闭队列时被关闭并替换，用于唤醒等待任务的协程
+	notEmpty chan struct{}
+	// notFull 在取出任务或关闭队列时被关闭并替换，用于唤醒等待空间的协程
+	notFull chan struct{}
+}
+
+func newBlockingQueue(store store, capacity int, delay bool) *blockingQueue {
+	return &blockingQueue{
+		store:    store,
+		capacity: capacity,
+		delay:    delay,
+		notEmpty: make(chan struct{}),
+		notFull:  make(chan struct{}),
+	}
+}
+
+func (q *blockingQueue) Offer(task *Task) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed || q.full() {
+		return false
+	}
+	q.push(task)
+	return true
+}
+
+func (q *blockingQueue) Put(ctx context.Context, task *Task) error {
+	for {
+		q.mu.Lock()
+		if q.closed {
+			q.mu.Unlock()
+			return ErrQueueClosed
+		}
+		if !q.full() {
+			q.push(task)
+			q.mu.Unlock()
+			return nil
+		}
+		notFull := q.notFull
+		q.mu.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-notFull:
+		}
+	}
+}
+
+func (q *blockingQueue) Take(ctx context.Context) (*Task, error) {
+	for {
+		q.mu.Lock()
+		task, wait := q.poll()
+		if task != nil {
+			q.mu.Unlock()
+			return task, nil
+		}
+		if q.closed && q.store.len() == 0 {
+			q.mu.Unlock()
+			return nil, ErrQueueClosed
+		}
+		notEmpty := q.notEmpty
+		q.mu.Unlock()
+		if wait <= 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-notEmpty:
+			}
+			continue
+		}
+		// 等待队头的延迟任务到期或有更早到期的任务放入
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-notEmpty:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+func (q *blockingQueue) Poll() (*Task, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	task, _ := q.poll()
+	return task, task != nil
+}
+
+func (q *blockingQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.store.len()
+}
+
+func (q *blockingQueue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	q.signal(&q.notEmpty)
+	q.signal(&q.notFull)
+}
+
+// full 返回队列是否已满，调用时必须持有锁
+func (q *blockingQueue) full() bool {
+	return q.capacity > 0 && q.store.len() >= q.capacity
+}
+
+// push 放入任务并唤醒等待任务的协程，调用时必须持有锁
+func (q *blockingQueue) push(task *Task) {
+	q.seq++
+	task.seq = q.seq
+	q.store.push(task)
+	q.signal(&q.notEmpty)
+}
+
+// poll 取出下一个可执行的任务，没有时返回队头延迟任务还需等待的时长，调用时必须持有锁
+func (q *blockingQueue) poll() (*Task, time.Duration) {
+	head := q.store.peek()
+	if head == nil {
+		return nil, 0
+	}
+	if q.delay {
+		if wait := time.Until(head.At); wait > 0 {
+			return nil, wait
+		}
+	}
+	q.store.pop()
+	q.signal(&q.notFull)
+	return head, 0
+}
+
+// signal 关闭并替换通知通道，唤醒所有等待的协程，调用时必须持有锁
+func (q *blockingQueue) signal(c *chan struct{}) {
+	close(*c)
+	*c = make(chan struct{})
+}
+
+type fifoStore struct {
+	tasks []*Task
+}
+
+func (s *fifoStore) push(task *Task) { s.tasks = append(s.tasks, task) }
+
+func (s *fifoStore) peek() *Task {
+	if len(s.tasks) == 0 {
+		return nil
+	}
+	return s.tasks[0]
+}
+
+func (s *fifoStore) pop() *Task {
+	task := s.tasks[0]
+	s.tasks[0] = nil
+	s.tasks = s.tasks[1:]
+	return task
+}
+
+func (s *fifoStore) len() int { return len(s.tasks) }
+
+type lifoStore struct {
+	tasks []*Task
+}
+
+func (s *lifoStore) push(task *Task) { s.tasks = append(s.tasks, task) }
+
+func (s *lifoStore) peek() *Task {
+	if len(s.tasks) == 0 {
+		return nil
+	}
+	return s.tasks[len(s.tasks)-1]
+}
+
+func (s *lifoStore) pop() *Task {
+	task := s.tasks[len(s.tasks)-1]
+	s.tasks[len(s.tasks)-1] = nil
+	s.tasks = s.tasks[:len(s.tasks)-1]
+	return task
+}
+
+func (s *lifoStore) len() int { return len(s.tasks) }
+
+// heapStore 按 less 排序的任务堆
+type heapStore struct {
+	tasks []*Task
+	less  func(a, b *Task) bool
+}
+
+func (s *heapStore) push(task *Task) { heap.Push(s, task) }
+
+func (s *heapStore) peek() *Task {
+	if len(s.tasks) == 0 {
+		return nil
+	}
+	return s.tasks[0]
+}
+
+func (s *heapStore) pop() *Task { return heap.Pop(s).(*Task) }
+
+func (s *heapStore) len() int { return len(s.tasks) }
+
+func (s *heapStore) Len() int { return len(s.tasks) }
+
+func (s *heapStore) Less(i, j int) bool { return s.less(s.tasks[i], s.tasks[j]) }
+
+func (s *heapStore) Swap(i, j int) { s.tasks[i], s.tasks[j] = s.tasks[j], s.tasks[i] }
+
+func (s *heapStore) Push(x any) {
+	task, _ := x.(*Task)
+	s.tasks = append(s.tasks, task)
+}
+
+func (s *heapStore) Pop() any {
+	n := len(s.tasks)
+	task := s.tasks[n-1]
+	s.tasks[n-1] = nil
+	s.tasks = s.tasks[:n-1]
+	return task
+}
@@ -0,0 +1,234 @@
+package sample_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/soyacen/goconc/gofer/sample"
+)
+
+// drain 按出队顺序返回队列中所有任务的优先级
+func drain(q sample.Queue) []int {
+	var got []int
+	for {
+		task, ok := q.Poll()
+		if !ok {
+			return got
+		}
+		got = append(got, task.Priority)
+	}
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestQueueOrder 测试各种队列的出队顺序
+func TestQueueOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		queue sample.Queue
+		want  []int
+	}{
+		{name: "fifo", queue: sample.NewFIFOQueue(0), want: []int{1, 3, 2, 3}},
+		{name: "lifo", queue: sample.NewLIFOQueue(0), want: []int{3, 2, 3, 1}},
+		{name: "priority", queue: sample.NewPriorityQueue(0), want: []int{3, 3, 2, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, p := range []int{1, 3, 2, 3} {
+				if !tt.queue.Offer(&sample.Task{Priority: p}) {
+					t.Fatal("Expected Offer to succeed")
+				}
+			}
+			if got := drain(tt.queue); !equal(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestPriorityQueueStable 测试相同优先级的任务先进先出
+func TestPriorityQueueStable(t *testing.T) {
+	q := sample.NewPriorityQueue(0)
+	first, second := &sample.Task{Priority: 1}, &sample.Task{Priority: 1}
+	q.Offer(first)
+	q.Offer(second)
+	if task, _ := q.Poll(); task != first {
+		t.Error("Expected tasks with equal priority to be FIFO")
+	}
+}
+
+// TestDelayQueue 测试延迟队列只在任务到期后出队
+func TestDelayQueue(t *testing.T) {
+	q := sample.NewDelayQueue(0)
+	now := time.Now()
+	q.Offer(&sample.Task{Priority: 2, At: now.Add(40 * time.Millisecond)})
+	q.Offer(&sample.Task{Priority: 1, At: now.Add(20 * time.Millisecond)})
+	if _, ok := q.Poll(); ok {
+		t.Error("Expected no due task")
+	}
+	for _, want := range []int{1, 2} {
+		task, err := q.Take(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if task.Priority != want {
+			t.Errorf("Expected task %d, got %d", want, task.Priority)
+		}
+		if time.Now().Before(task.At) {
+			t.Error("Task taken before it was due")
+		}
+	}
+}
+
+// TestQueueCapacity 测试有界队列的 Offer 和 Put
+func TestQueueCapacity(t *testing.T) {
+	q := sample.NewFIFOQueue(1)
+	if !q.Offer(&sample.Task{}) {
+		t.Fatal("Expected Offer to succeed")
+	}
+	if q.Offer(&sample.Task{}) {
+		t.Error("Expected Offer to fail on a full queue")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := q.Put(ctx, &sample.Task{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got: %v", err)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		q.Poll()
+	}()
+	if err := q.Put(context.Background(), &sample.Task{}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if q.Len() != 1 {
+		t.Errorf("Expected length 1, got %d", q.Len())
+	}
+}
+
+// TestQueueClose 测试关闭队列后不能放入任务，但可以取出已有的任务
+func TestQueueClose(t *testing.T) {
+	q := sample.NewFIFOQueue(0)
+	q.Offer(&sample.Task{Priority: 1})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var takeErr error
+	go func() {
+		defer wg.Done()
+		q.Take(context.Background())
+		_, takeErr = q.Take(context.Background())
+	}()
+	time.Sleep(20 * time.Millisecond)
+	q.Close()
+	wg.Wait()
+	if !errors.Is(takeErr, sample.ErrQueueClosed) {
+		t.Errorf("Expected ErrQueueClosed, got: %v", takeErr)
+	}
+	if q.Offer(&sample.Task{}) {
+		t.Error("Expected Offer to fail on a closed queue")
+	}
+	if err := q.Put(context.Background(), &sample.Task{}); !errors.Is(err, sample.ErrQueueClosed) {
+		t.Errorf("Expected ErrQueueClosed, got: %v", err)
+	}
+}
+
+// TestGoPriority 测试优先级高的任务先被执行
+func TestGoPriority(t *testing.T) {
+	g := sample.New(
+		sample.CorePoolSize(1),
+		sample.MaximumPoolSize(1),
+		sample.WorkQueue(sample.NewPriorityQueue(0)),
+	)
+	release := make(chan struct{})
+	g.Go(func() { <-release })
+
+	var mu sync.Mutex
+	var got []int
+	for _, p := range []int{1, 5, 3} {
+		if err := g.GoPriority(func() {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, p)
+		}, p); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := g.Close(ctx); err != nil {
+		t.Errorf("Unexpected error closing pool: %v", err)
+	}
+	if want := []int{5, 3, 1}; !equal(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+// TestGoDelay 测试延迟任务在到期后才被执行
+func TestGoDelay(t *testing.T) {
+	g := sample.New(
+		sample.CorePoolSize(1),
+		sample.MaximumPoolSize(2),
+		sample.WorkQueue(sample.NewDelayQueue(0)),
+	)
+	start := time.Now()
+	executed := make(chan time.Time, 1)
+	if err := g.GoDelay(func() { executed <- time.Now() }, 30*time.Millisecond); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	select {
+	case at := <-executed:
+		if at.Sub(start) < 30*time.Millisecond {
+			t.Errorf("Task executed too early, after %v", at.Sub(start))
+		}
+	case <-time.After(time.Second):
+		t.Error("task was not executed within timeout")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := g.Close(ctx); err != nil {
+		t.Errorf("Unexpected error closing pool: %v", err)
+	}
+}
+
+// TestGoUnsupported 测试任务队列不支持优先级或延迟时，GoPriority 和 GoDelay 返回 ErrUnsupported
+func TestGoUnsupported(t *testing.T) {
+	g := sample.New(
+		sample.CorePoolSize(1),
+		sample.MaximumPoolSize(1),
+		sample.WorkQueue(sample.NewFIFOQueue(0)),
+	)
+	if err := g.GoPriority(func() {}, 1); !errors.Is(err, sample.ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got: %v", err)
+	}
+	if err := g.GoDelay(func() {}, time.Millisecond); !errors.Is(err, sample.ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got: %v", err)
+	}
+	if stats := g.Stats(); stats.Submitted != 0 {
+		t.Errorf("Expected no submitted tasks, got %+v", stats)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := g.Close(ctx); err != nil {
+		t.Errorf("Unexpected error closing pool: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package sample
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RejectionPolicy 处理任务队列已满时被 Go 拒绝的任务，返回值作为 Go 的返回值
+// 返回 nil 但不执行任务的策略必须调用 Gofer.Discard，通知等待任务结果的调用者
+// g: 拒绝任务的线程池
+// task: 被拒绝的任务，Run 已经包装了 panic 捕获
+type RejectionPolicy func(g *Gofer, task *Task) error
+
+// AbortPolicy 拒绝任务并返回 ErrPoolFull
+func AbortPolicy(g *Gofer, task *Task) error {
+	return ErrPoolFull
+}
+
+// CallerRunsPolicy 在调用 Go 的协程中直接执行任务，从而减慢任务的提交速度；池已关闭时返回 ErrPoolClosed
+func CallerRunsPolicy(g *Gofer, task *Task) error {
+	if g.closed.Load() {
+		return ErrPoolClosed
+	}
+	task.Run()
+	return nil
+}
+
+// DiscardPolicy 丢弃任务并返回 nil，通过 Task.OnDiscard 以 ErrPoolFull 通知任务被丢弃
+func DiscardPolicy(g *Gofer, task *Task) error {
+	g.Discard(task, ErrPoolFull)
+	return nil
+}
+
+// DiscardOldestPolicy 丢弃任务队列头部的任务，即下一个将被执行的任务，然后重新尝试将任务放入队列；
+// 被丢弃的任务通过 Task.OnDiscard 以 ErrPoolFull 得到通知；队列中没有可丢弃的任务时返回 ErrPoolFull
+func DiscardOldestPolicy(g *Gofer, task *Task) error {
+	for {
+		if g.WorkQueue.Offer(task) {
+			return nil
+		}
+		if g.closed.Load() {
+			return ErrPoolClosed
+		}
+		oldest, ok := g.WorkQueue.Poll()
+		if !ok {
+			return ErrPoolFull
+		}
+		g.Discard(oldest, ErrPoolFull)
+	}
+}
+
+// BlockPolicy 阻塞直到任务队列有空间，超过 timeout 仍没有空间时返回 ErrPoolFull；timeout 小于等于 0 时一直阻塞
+func BlockPolicy(timeout time.Duration) RejectionPolicy {
+	return func(g *Gofer, task *Task) error {
+		ctx := context.Background()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		err := g.put(ctx, task)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return ErrPoolFull
+		}
+		return err
+	}
+}
@@ -0,0 +1,171 @@
+package sample_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/soyacen/goconc/gofer"
+	"github.com/soyacen/goconc/gofer/sample"
+)
+
+// newFullPool 创建一个工作线程和任务队列都已被占满的池，关闭 release 后任务开始执行
+func newFullPool(t *testing.T, policy sample.RejectionPolicy, queued func()) (*sample.Gofer, chan struct{}) {
+	g := sample.New(
+		sample.CorePoolSize(1),
+		sample.MaximumPoolSize(1),
+		sample.WorkQueueSize(1),
+		sample.Rejection(policy),
+	)
+	release := make(chan struct{})
+	if err := g.Go(func() { <-release }); err != nil {
+		t.Fatalf("Unexpected error submitting first task: %v", err)
+	}
+	if err := g.Go(queued); err != nil {
+		t.Fatalf("Unexpected error submitting queued task: %v", err)
+	}
+	return g, release
+}
+
+func closePool(t *testing.T, g *sample.Gofer) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := g.Close(ctx); err != nil {
+		t.Errorf("Unexpected error closing pool: %v", err)
+	}
+}
+
+// TestAbortPolicy 测试默认的拒绝策略
+func TestAbortPolicy(t *testing.T) {
+	g, release := newFullPool(t, sample.AbortPolicy, func() {})
+	if err := g.Go(func() {}); !errors.Is(err, sample.ErrPoolFull) {
+		t.Errorf("Expected ErrPoolFull, got: %v", err)
+	}
+	close(release)
+	closePool(t, g)
+}
+
+// TestCallerRunsPolicy 测试被拒绝的任务在调用者协程中执行
+func TestCallerRunsPolicy(t *testing.T) {
+	g, release := newFullPool(t, sample.CallerRunsPolicy, func() {})
+	executed := false
+	if err := g.Go(func() { executed = true }); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !executed {
+		t.Error("Expected task to run in the caller goroutine")
+	}
+	close(release)
+	closePool(t, g)
+}
+
+// TestDiscardPolicy 测试被拒绝的任务被静默丢弃
+func TestDiscardPolicy(t *testing.T) {
+	g, release := newFullPool(t, sample.DiscardPolicy, func() {})
+	var executed atomic.Bool
+	if err := g.Go(func() { executed.Store(true) }); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	close(release)
+	closePool(t, g)
+	if executed.Load() {
+		t.Error("Expected task to be discarded")
+	}
+}
+
+// TestDiscardOldestPolicy 测试丢弃队列中等待最久的任务
+func TestDiscardOldestPolicy(t *testing.T) {
+	var oldest, newest atomic.Bool
+	g, release := newFullPool(t, sample.DiscardOldestPolicy, func() { oldest.Store(true) })
+	if err := g.Go(func() { newest.Store(true) }); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	close(release)
+	closePool(t, g)
+	if oldest.Load() || !newest.Load() {
+		t.Errorf("Expected oldest task to be discarded, oldest executed: %v, newest executed: %v", oldest.Load(), newest.Load())
+	}
+}
+
+// TestBlockPolicy 测试阻塞直到队列有空间或超时
+func TestBlockPolicy(t *testing.T) {
+	g, release := newFullPool(t, sample.BlockPolicy(20*time.Millisecond), func() {})
+	if err := g.Go(func() {}); !errors.Is(err, sample.ErrPoolFull) {
+		t.Errorf("Expected ErrPoolFull after timeout, got: %v", err)
+	}
+	close(release)
+	closePool(t, g)
+
+	g, release = newFullPool(t, sample.BlockPolicy(time.Second), func() {})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	if err := g.Go(func() {}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	closePool(t, g)
+}
+
+// TestSubmitWithRejectionPolicies 测试在每种拒绝策略下，gofer.Submit 返回的 Future 都会完成
+func TestSubmitWithRejectionPolicies(t *testing.T) {
+	task := func(ctx context.Context) (int, error) { return 1, nil }
+	tests := []struct {
+		name      string
+		policy    sample.RejectionPolicy
+		submitErr error
+		getErr    error
+	}{
+		{name: "Abort", policy: sample.AbortPolicy, submitErr: sample.ErrPoolFull},
+		{name: "CallerRuns", policy: sample.CallerRunsPolicy},
+		{name: "Discard", policy: sample.DiscardPolicy, getErr: sample.ErrPoolFull},
+		{name: "DiscardOldest", policy: sample.DiscardOldestPolicy},
+		{name: "Block", policy: sample.BlockPolicy(20 * time.Millisecond), submitErr: sample.ErrPoolFull},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := sample.New(
+				sample.CorePoolSize(1),
+				sample.MaximumPoolSize(1),
+				sample.WorkQueueSize(1),
+				sample.Rejection(tt.policy),
+			)
+			release := make(chan struct{})
+			if err := g.Go(func() { <-release }); err != nil {
+				t.Fatalf("Unexpected error submitting first task: %v", err)
+			}
+			queued, err := gofer.Submit(g, task)
+			if err != nil {
+				t.Fatalf("Unexpected error submitting queued task: %v", err)
+			}
+			f, err := gofer.Submit(g, task)
+			if !errors.Is(err, tt.submitErr) {
+				t.Fatalf("Expected Submit error %v, got: %v", tt.submitErr, err)
+			}
+			close(release)
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			if f != nil {
+				if _, err := f.Get(ctx); !errors.Is(err, tt.getErr) {
+					t.Errorf("Expected Get error %v, got: %v", tt.getErr, err)
+				}
+			}
+			// DiscardOldestPolicy 丢弃了先提交的任务
+			var queuedErr error
+			if tt.name == "DiscardOldest" {
+				queuedErr = sample.ErrPoolFull
+			}
+			if _, err := queued.Get(ctx); !errors.Is(err, queuedErr) {
+				t.Errorf("Expected queued Get error %v, got: %v", queuedErr, err)
+			}
+			closePool(t, g)
+			if stats := g.Stats(); tt.getErr != nil || queuedErr != nil {
+				if stats.Discarded != 1 {
+					t.Errorf("Expected 1 discarded task, got %+v", stats)
+				}
+			}
+		})
+	}
+}
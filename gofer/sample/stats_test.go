@@ -0,0 +1,158 @@
+package sample_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/soyacen/goconc/gofer"
+	"github.com/soyacen/goconc/gofer/sample"
+)
+
+// TestStats 测试统计快照
+func TestStats(t *testing.T) {
+	g := sample.New(
+		sample.CorePoolSize(1),
+		sample.MaximumPoolSize(1),
+		sample.WorkQueueSize(1),
+		sample.Recover(func(p any, stack []byte) {}),
+	)
+	defer g.Close(context.Background())
+
+	release := make(chan struct{})
+	if err := g.Go(func() { <-release }); err != nil {
+		t.Fatalf("Go() returned unexpected error: %v", err)
+	}
+	if err := g.Go(func() { panic("oops") }); err != nil {
+		t.Fatalf("Go() returned unexpected error: %v", err)
+	}
+	if err := g.Go(func() {}); !errors.Is(err, sample.ErrPoolFull) {
+		t.Fatalf("Expected ErrPoolFull, got: %v", err)
+	}
+	if !waitFor(func() bool { return g.Stats().ActiveWorkers == 1 }) {
+		t.Fatalf("Expected 1 active worker, got %+v", g.Stats())
+	}
+	stats := g.Stats()
+	if stats.CoreWorkers != 1 || stats.EdgeWorkers != 0 || stats.QueueDepth != 1 || stats.Submitted != 2 || stats.Rejected != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+
+	close(release)
+	if !waitFor(func() bool { s := g.Stats(); return s.Completed == 1 && s.Failed == 1 && s.ActiveWorkers == 0 }) {
+		t.Errorf("Unexpected stats %+v", g.Stats())
+	}
+	var _ gofer.StatsGofer = g
+}
+
+// TestStatsRejected 测试只有提交失败的任务计入 Rejected
+func TestStatsRejected(t *testing.T) {
+	var rejects atomic.Int32
+	g := sample.New(
+		sample.CorePoolSize(1),
+		sample.MaximumPoolSize(1),
+		sample.WorkQueueSize(1),
+		sample.Rejection(sample.CallerRunsPolicy),
+		sample.Hooks(gofer.Hooks{OnReject: func(err error) { rejects.Add(1) }}),
+	)
+	release := make(chan struct{})
+	for _, task := range []func(){func() { <-release }, func() {}, func() {}} {
+		if err := g.Go(task); err != nil {
+			t.Fatalf("Go() returned unexpected error: %v", err)
+		}
+	}
+	close(release)
+	if err := g.Close(context.Background()); err != nil {
+		t.Fatalf("Close() returned unexpected error: %v", err)
+	}
+	if stats := g.Stats(); stats.Submitted != 3 || stats.Completed != 3 || stats.Rejected != 0 || rejects.Load() != 0 {
+		t.Errorf("Unexpected stats %+v, rejects %d", stats, rejects.Load())
+	}
+	if err := g.Go(func() {}); !errors.Is(err, sample.ErrPoolClosed) {
+		t.Fatalf("Expected ErrPoolClosed, got: %v", err)
+	}
+	if stats := g.Stats(); stats.Rejected != 1 || rejects.Load() != 1 {
+		t.Errorf("Unexpected stats %+v, rejects %d", stats, rejects.Load())
+	}
+}
+
+// TestStatsGoContextSkipped 测试通过 GoContext 提交、执行前 ctx 已经结束的任务计入 Discarded
+func TestStatsGoContextSkipped(t *testing.T) {
+	g := sample.New(
+		sample.CorePoolSize(1),
+		sample.MaximumPoolSize(1),
+		sample.WorkQueueSize(1),
+	)
+	release := make(chan struct{})
+	if err := g.Go(func() { <-release }); err != nil {
+		t.Fatalf("Go() returned unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	var ran atomic.Bool
+	if err := g.GoContext(ctx, func(ctx context.Context) { ran.Store(true) }); err != nil {
+		t.Fatalf("GoContext() returned unexpected error: %v", err)
+	}
+	cancel()
+	close(release)
+	if err := g.Close(context.Background()); err != nil {
+		t.Fatalf("Close() returned unexpected error: %v", err)
+	}
+	if ran.Load() {
+		t.Error("Expected task to be skipped")
+	}
+	if stats := g.Stats(); stats.Submitted != 2 || stats.Completed != 1 || stats.Discarded != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+// TestHooks 测试生命周期回调
+func TestHooks(t *testing.T) {
+	var before, after, failed, panics, rejects, starts, exits atomic.Int32
+	g := sample.New(
+		sample.CorePoolSize(1),
+		sample.MaximumPoolSize(2),
+		sample.WorkQueueSize(1),
+		sample.Recover(func(p any, stack []byte) {}),
+		sample.Hooks(gofer.Hooks{
+			BeforeExecute: func(wait time.Duration) { before.Add(1) },
+			AfterExecute: func(run time.Duration, panicked bool) {
+				after.Add(1)
+				if panicked {
+					failed.Add(1)
+				}
+			},
+			OnReject:      func(err error) { rejects.Add(1) },
+			OnPanic:       func(p any, stack []byte) { panics.Add(1) },
+			OnWorkerStart: func() { starts.Add(1) },
+			OnWorkerExit:  func() { exits.Add(1) },
+		}),
+	)
+
+	release := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		if err := g.Go(func() { <-release }); err != nil {
+			t.Fatalf("Go() returned unexpected error: %v", err)
+		}
+	}
+	if err := g.Go(func() { panic("oops") }); err != nil {
+		t.Fatalf("Go() returned unexpected error: %v", err)
+	}
+	if err := g.Go(func() {}); !errors.Is(err, sample.ErrPoolFull) {
+		t.Fatalf("Expected ErrPoolFull, got: %v", err)
+	}
+	close(release)
+	if err := g.Close(context.Background()); err != nil {
+		t.Fatalf("Close() returned unexpected error: %v", err)
+	}
+
+	if before.Load() != 3 || after.Load() != 3 || failed.Load() != 1 || panics.Load() != 1 {
+		t.Errorf("Unexpected execute hooks: before=%d after=%d failed=%d panics=%d", before.Load(), after.Load(), failed.Load(), panics.Load())
+	}
+	if rejects.Load() != 1 {
+		t.Errorf("Expected 1 rejection, got %d", rejects.Load())
+	}
+	if starts.Load() != 2 || exits.Load() != 2 {
+		t.Errorf("Expected 2 workers to start and exit, got %d and %d", starts.Load(), exits.Load())
+	}
+}
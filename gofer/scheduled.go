@@ -0,0 +1,326 @@
+package gofer
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrSchedulerClosed = errors.New("gofer: scheduler is closed")
+	ErrTaskCanceled    = errors.New("gofer: scheduled task is canceled")
+	ErrTaskPanicked    = errors.New("gofer: scheduled task panicked")
+	ErrInvalidPeriod   = errors.New("gofer: period must be positive")
+)
+
+var _ Gofer = (*ScheduledGofer)(nil)
+
+// ScheduledGofer 在任意 Gofer 实现上执行延迟任务和周期任务
+// 所有待执行的任务保存在一个按执行时间排序的堆中，由一个调度协程和一个定时器驱动，
+// 到期的任务通过被包装的执行器执行，因此大量定时任务的开销很小。
+// 到期的任务在单独的协程中提交给被包装的执行器，即使执行器的 Go 会阻塞（如 tunny 同步执行任务，
+// 或 Sample 使用 CallerRunsPolicy、BlockPolicy），也不会延误其他任务的调度
+type ScheduledGofer struct {
+	// Gofer 执行到期任务的执行器
+	Gofer Gofer
+
+	mu     sync.Mutex
+	tasks  scheduledHeap
+	seq    uint64
+	closed bool
+	// wake 在堆顶任务改变时唤醒调度协程
+	wake chan struct{}
+	// done 在关闭时被关闭，通知调度协程退出
+	done chan struct{}
+	// stopped 在调度协程退出后被关闭
+	stopped chan struct{}
+	once    sync.Once
+	// firing 正在向被包装的执行器提交任务的协程
+	firing sync.WaitGroup
+}
+
+// NewScheduledGofer 创建一个 ScheduledGofer，并启动调度协程
+// g: 执行到期任务的执行器
+func NewScheduledGofer(g Gofer) *ScheduledGofer {
+	s := &ScheduledGofer{
+		Gofer:   g,
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.loop()
+	return s
+}
+
+// ScheduledTask 是被调度任务的句柄
+type ScheduledTask struct {
+	s *ScheduledGofer
+	f func()
+	// at 下一次执行的时间
+	at time.Time
+	// period 大于 0 表示固定频率，小于 0 表示固定延迟，等于 0 表示只执行一次
+	period time.Duration
+	seq    uint64
+	// index 任务在堆中的位置，不在堆中时为 -1
+	index    int
+	finished bool
+	err      error
+	done     chan struct{}
+}
+
+// Cancel 取消任务，之后任务不会再被执行，正在执行的任务会执行完
+// 返回是否取消成功，任务已经结束时返回 false
+func (t *ScheduledTask) Cancel() bool {
+	t.s.mu.Lock()
+	defer t.s.mu.Unlock()
+	if t.finished {
+		return false
+	}
+	if t.index >= 0 {
+		heap.Remove(&t.s.tasks, t.index)
+	}
+	t.finish(ErrTaskCanceled)
+	return true
+}
+
+// Done 返回一个在任务结束时被关闭的通道
+// 只执行一次的任务在执行完后结束，周期任务在被取消、发生 panic、提交失败或执行器关闭后结束
+func (t *ScheduledTask) Done() <-chan struct{} {
+	return t.done
+}
+
+// Err 返回任务结束的原因，任务未结束或正常执行完时返回 nil
+// 被取消时返回 ErrTaskCanceled，发生 panic 时返回 ErrTaskPanicked，执行器关闭时返回 ErrSchedulerClosed，
+// 提交到被包装的执行器失败或被丢弃时返回提交或丢弃的错误
+func (t *ScheduledTask) Err() error {
+	t.s.mu.Lock()
+	defer t.s.mu.Unlock()
+	return t.err
+}
+
+// finish 结束任务，调用时必须持有锁
+func (t *ScheduledTask) finish(err error) {
+	t.finished = true
+	t.err = err
+	close(t.done)
+}
+
+// Go 立即通过被包装的执行器执行 f
+func (s *ScheduledGofer) Go(f func()) error {
+	s.mu.Lock()
+	closed := s.closed
+	s.mu.Unlock()
+	if closed {
+		return ErrSchedulerClosed
+	}
+	return s.Gofer.Go(f)
+}
+
+// Schedule 在 delay 之后执行一次 f
+// f: 要执行的任务函数
+// delay: 延迟时长，小于等于 0 时尽快执行
+func (s *ScheduledGofer) Schedule(f func(), delay time.Duration) (*ScheduledTask, error) {
+	return s.schedule(f, delay, 0)
+}
+
+// ScheduleAtFixedRate 在 initialDelay 之后以固定频率周期执行 f，第 n 次执行的时间为 initialDelay + n * period
+// 一次执行超过 period 时，下一次执行会推迟，但不会并发执行
+// f: 要执行的任务函数，f 发生 panic 后任务不再被执行
+// initialDelay: 第一次执行的延迟时长
+// period: 执行的周期，必须大于 0
+func (s *ScheduledGofer) ScheduleAtFixedRate(f func(), initialDelay, period time.Duration) (*ScheduledTask, error) {
+	if period <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	return s.schedule(f, initialDelay, period)
+}
+
+// ScheduleWithFixedDelay 在 initialDelay 之后周期执行 f，每次执行结束后等待 delay 再执行下一次
+// f: 要执行的任务函数，f 发生 panic 后任务不再被执行
+// initialDelay: 第一次执行的延迟时长
+// delay: 两次执行之间的间隔，必须大于 0
+func (s *ScheduledGofer) ScheduleWithFixedDelay(f func(), initialDelay, delay time.Duration) (*ScheduledTask, error) {
+	if delay <= 0 {
+		return nil, ErrInvalidPeriod
+	}
+	return s.schedule(f, initialDelay, -delay)
+}
+
+// Close 取消所有未执行的任务，停止调度协程，等待正在进行的提交结束，然后关闭被包装的执行器
+// ctx: 上下文，用于控制被包装的执行器的关闭超时
+func (s *ScheduledGofer) Close(ctx context.Context) error {
+	s.once.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		for _, t := range s.tasks {
+			t.index = -1
+			t.finish(ErrSchedulerClosed)
+		}
+		s.tasks = nil
+		s.mu.Unlock()
+		close(s.done)
+		<-s.stopped
+		s.firing.Wait()
+	})
+	return s.Gofer.Close(ctx)
+}
+
+func (s *ScheduledGofer) schedule(f func(), delay, period time.Duration) (*ScheduledTask, error) {
+	t := &ScheduledTask{
+		s:      s,
+		f:      f,
+		at:     time.Now().Add(max(delay, 0)),
+		period: period,
+		index:  -1,
+		done:   make(chan struct{}),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrSchedulerClosed
+	}
+	s.push(t)
+	return t, nil
+}
+
+// push 将任务放入堆中，任务成为堆顶时唤醒调度协程，调用时必须持有锁
+func (s *ScheduledGofer) push(t *ScheduledTask) {
+	s.seq++
+	t.seq = s.seq
+	heap.Push(&s.tasks, t)
+	if t.index == 0 {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// loop 等待堆顶任务到期，将到期的任务交给被包装的执行器执行，直到关闭
+func (s *ScheduledGofer) loop() {
+	defer close(s.stopped)
+	timer := time.NewTimer(0)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		var due []*ScheduledTask
+		wait := time.Duration(-1)
+		now := time.Now()
+		s.mu.Lock()
+		for len(s.tasks) > 0 {
+			if d := s.tasks[0].at.Sub(now); d > 0 {
+				wait = d
+				break
+			}
+			due = append(due, heap.Pop(&s.tasks).(*ScheduledTask))
+		}
+		s.mu.Unlock()
+		for _, t := range due {
+			// 被包装的执行器的 Go 可能阻塞，不能在调度协程中提交
+			s.firing.Add(1)
+			go func() {
+				defer s.firing.Done()
+				s.fire(t)
+			}()
+		}
+		if wait >= 0 {
+			timer.Reset(wait)
+		}
+		select {
+		case <-s.done:
+			return
+		case <-s.wake:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// fire 通过被包装的执行器执行任务，提交失败或任务被丢弃时结束任务
+func (s *ScheduledGofer) fire(t *ScheduledTask) {
+	fail := func(err error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if !t.finished {
+			t.finish(err)
+		}
+	}
+	if err := GoDiscard(s.Gofer, func() { s.run(t) }, fail); err != nil {
+		fail(err)
+	}
+}
+
+// run 执行任务，执行完后计算周期任务下一次执行的时间并重新放入堆中
+func (s *ScheduledGofer) run(t *ScheduledTask) {
+	s.mu.Lock()
+	if !t.finished && s.closed {
+		t.finish(ErrSchedulerClosed)
+	}
+	finished := t.finished
+	s.mu.Unlock()
+	if finished {
+		return
+	}
+	completed := false
+	defer func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if t.finished {
+			return
+		}
+		switch {
+		case !completed:
+			// panic 会继续抛出，交给被包装的执行器处理
+			t.finish(ErrTaskPanicked)
+		case t.period == 0:
+			t.finish(nil)
+		case s.closed:
+			t.finish(ErrSchedulerClosed)
+		default:
+			if t.period > 0 {
+				t.at = t.at.Add(t.period)
+			} else {
+				t.at = time.Now().Add(-t.period)
+			}
+			s.push(t)
+		}
+	}()
+	t.f()
+	completed = true
+}
+
+// scheduledHeap 按执行时间排序的任务堆，执行时间相同的任务先进先出
+type scheduledHeap []*ScheduledTask
+
+func (h scheduledHeap) Len() int { return len(h) }
+
+func (h scheduledHeap) Less(i, j int) bool {
+	if !h[i].at.Equal(h[j].at) {
+		return h[i].at.Before(h[j].at)
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h scheduledHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *scheduledHeap) Push(x any) {
+	t, _ := x.(*ScheduledTask)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *scheduledHeap) Pop() any {
+	old := *h
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil
+	t.index = -1
+	*h = old[:n-1]
+	return t
+}
@@ -0,0 +1,277 @@
+package gofer
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScheduledGofer_Schedule(t *testing.T) {
+	s := NewScheduledGofer(goGofer{})
+	defer s.Close(context.Background())
+
+	var order []int
+	results := make(chan int, 3)
+	for _, i := range []int{3, 1, 2} {
+		if _, err := s.Schedule(func() { results <- i }, time.Duration(i)*20*time.Millisecond); err != nil {
+			t.Fatalf("Schedule() returned unexpected error: %v", err)
+		}
+	}
+	start := time.Now()
+	for range 3 {
+		order = append(order, <-results)
+	}
+	if order[0] != 1 || order[1] != 2 || order[2] != 3 {
+		t.Errorf("expected tasks to run in order of delay, got %v", order)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("expected tasks to be delayed, took %v", elapsed)
+	}
+}
+
+func TestScheduledGofer_Done(t *testing.T) {
+	s := NewScheduledGofer(goGofer{})
+	defer s.Close(context.Background())
+
+	task, err := s.Schedule(func() {}, 0)
+	if err != nil {
+		t.Fatalf("Schedule() returned unexpected error: %v", err)
+	}
+	select {
+	case <-task.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected task to be done")
+	}
+	if err := task.Err(); err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+	if task.Cancel() {
+		t.Error("expected Cancel() to return false for a finished task")
+	}
+}
+
+// inlineGofer 在 Go 中同步执行任务，与 tunny 的行为相同
+type inlineGofer struct{}
+
+func (inlineGofer) Go(f func()) error {
+	f()
+	return nil
+}
+
+func (inlineGofer) Close(ctx context.Context) error { return nil }
+
+func TestScheduledGofer_BlockingGo(t *testing.T) {
+	s := NewScheduledGofer(inlineGofer{})
+	defer s.Close(context.Background())
+
+	release := make(chan struct{})
+	slow, err := s.Schedule(func() { <-release }, 0)
+	if err != nil {
+		t.Fatalf("Schedule() returned unexpected error: %v", err)
+	}
+	fast, err := s.Schedule(func() {}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Schedule() returned unexpected error: %v", err)
+	}
+	select {
+	case <-fast.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected a blocked Go not to delay other tasks")
+	}
+	close(release)
+	<-slow.Done()
+}
+
+func TestScheduledGofer_Cancel(t *testing.T) {
+	s := NewScheduledGofer(goGofer{})
+	defer s.Close(context.Background())
+
+	var ran atomic.Bool
+	task, err := s.Schedule(func() { ran.Store(true) }, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Schedule() returned unexpected error: %v", err)
+	}
+	if !task.Cancel() {
+		t.Fatal("expected Cancel() to return true")
+	}
+	if !errors.Is(task.Err(), ErrTaskCanceled) {
+		t.Errorf("expected ErrTaskCanceled, got %v", task.Err())
+	}
+	time.Sleep(100 * time.Millisecond)
+	if ran.Load() {
+		t.Error("expected canceled task not to run")
+	}
+}
+
+func TestScheduledGofer_FixedRate(t *testing.T) {
+	s := NewScheduledGofer(goGofer{})
+	defer s.Close(context.Background())
+
+	var count atomic.Int32
+	task, err := s.ScheduleAtFixedRate(func() { count.Add(1) }, 0, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("ScheduleAtFixedRate() returned unexpected error: %v", err)
+	}
+	time.Sleep(105 * time.Millisecond)
+	task.Cancel()
+	n := count.Load()
+	if n < 5 || n > 12 {
+		t.Errorf("expected about 11 runs, got %d", n)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if count.Load() > n+1 {
+		t.Errorf("expected canceled task to stop, got %d runs after %d", count.Load(), n)
+	}
+
+	if _, err := s.ScheduleAtFixedRate(func() {}, 0, 0); !errors.Is(err, ErrInvalidPeriod) {
+		t.Errorf("expected ErrInvalidPeriod, got %v", err)
+	}
+}
+
+func TestScheduledGofer_FixedDelay(t *testing.T) {
+	s := NewScheduledGofer(goGofer{})
+	defer s.Close(context.Background())
+
+	var running, overlapped atomic.Bool
+	var count atomic.Int32
+	task, err := s.ScheduleWithFixedDelay(func() {
+		if !running.CompareAndSwap(false, true) {
+			overlapped.Store(true)
+		}
+		time.Sleep(20 * time.Millisecond)
+		count.Add(1)
+		running.Store(false)
+	}, 0, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("ScheduleWithFixedDelay() returned unexpected error: %v", err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	task.Cancel()
+	if n := count.Load(); n < 2 || n > 4 {
+		t.Errorf("expected about 3 runs, got %d", n)
+	}
+	if overlapped.Load() {
+		t.Error("expected runs not to overlap")
+	}
+}
+
+// recoverGofer 为每个任务启动一个协程，并将任务中的 panic 发送到 panics
+type recoverGofer struct {
+	panics chan any
+}
+
+func (g recoverGofer) Go(f func()) error {
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				g.panics <- p
+			}
+		}()
+		f()
+	}()
+	return nil
+}
+
+func (recoverGofer) Close(ctx context.Context) error { return nil }
+
+func TestScheduledGofer_Panic(t *testing.T) {
+	g := recoverGofer{panics: make(chan any, 1)}
+	s := NewScheduledGofer(g)
+	defer s.Close(context.Background())
+
+	task, err := s.ScheduleAtFixedRate(func() { panic("oops") }, 0, time.Millisecond)
+	if err != nil {
+		t.Fatalf("ScheduleAtFixedRate() returned unexpected error: %v", err)
+	}
+	if p := <-g.panics; p != "oops" {
+		t.Errorf("expected panic to be re-raised, got %v", p)
+	}
+	<-task.Done()
+	if !errors.Is(task.Err(), ErrTaskPanicked) {
+		t.Errorf("expected ErrTaskPanicked, got %v", task.Err())
+	}
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case p := <-g.panics:
+		t.Errorf("expected panicked task not to run again, got %v", p)
+	default:
+	}
+}
+
+func TestScheduledGofer_Reject(t *testing.T) {
+	errFull := errors.New("full")
+	s := NewScheduledGofer(&queueGofer{err: errFull})
+	defer s.Close(context.Background())
+
+	task, err := s.Schedule(func() {}, 0)
+	if err != nil {
+		t.Fatalf("Schedule() returned unexpected error: %v", err)
+	}
+	<-task.Done()
+	if !errors.Is(task.Err(), errFull) {
+		t.Errorf("expected %v, got %v", errFull, task.Err())
+	}
+}
+
+func TestScheduledGofer_Discard(t *testing.T) {
+	errDiscarded := errors.New("discarded")
+	s := NewScheduledGofer(discardGofer{err: errDiscarded})
+	defer s.Close(context.Background())
+
+	task, err := s.ScheduleAtFixedRate(func() {}, 0, time.Millisecond)
+	if err != nil {
+		t.Fatalf("ScheduleAtFixedRate() returned unexpected error: %v", err)
+	}
+	<-task.Done()
+	if !errors.Is(task.Err(), errDiscarded) {
+		t.Errorf("expected %v, got %v", errDiscarded, task.Err())
+	}
+}
+
+func TestScheduledGofer_Close(t *testing.T) {
+	s := NewScheduledGofer(goGofer{})
+	task, err := s.Schedule(func() {}, time.Hour)
+	if err != nil {
+		t.Fatalf("Schedule() returned unexpected error: %v", err)
+	}
+	if err := s.Close(context.Background()); err != nil {
+		t.Fatalf("Close() returned unexpected error: %v", err)
+	}
+	<-task.Done()
+	if !errors.Is(task.Err(), ErrSchedulerClosed) {
+		t.Errorf("expected ErrSchedulerClosed, got %v", task.Err())
+	}
+	if _, err := s.Schedule(func() {}, 0); !errors.Is(err, ErrSchedulerClosed) {
+		t.Errorf("expected ErrSchedulerClosed, got %v", err)
+	}
+	if err := s.Go(func() {}); !errors.Is(err, ErrSchedulerClosed) {
+		t.Errorf("expected ErrSchedulerClosed, got %v", err)
+	}
+}
+
+func TestScheduledGofer_ManyTasks(t *testing.T) {
+	s := NewScheduledGofer(goGofer{})
+	defer s.Close(context.Background())
+
+	const n = 10000
+	var count atomic.Int32
+	tasks := make([]*ScheduledTask, 0, n)
+	for i := range n {
+		task, err := s.Schedule(func() { count.Add(1) }, time.Duration(10+i%50)*time.Millisecond)
+		if err != nil {
+			t.Fatalf("Schedule() returned unexpected error: %v", err)
+		}
+		if i%2 == 0 && !task.Cancel() {
+			t.Fatalf("expected Cancel() to return true")
+		}
+		tasks = append(tasks, task)
+	}
+	for _, task := range tasks {
+		<-task.Done()
+	}
+	if got := count.Load(); got != n/2 {
+		t.Errorf("expected %d runs, got %d", n/2, got)
+	}
+}
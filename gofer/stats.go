@@ -0,0 +1,235 @@
+package gofer
+
+import (
+	"context"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+)
+
+// Stats 是执行器的统计快照
+type Stats struct {
+	// CoreWorkers 存活的核心工作线程数，不区分核心与非核心线程的实现为 0
+	CoreWorkers int
+	// EdgeWorkers 存活的非核心工作线程数，不区分核心与非核心线程的实现为 0
+	EdgeWorkers int
+	// ActiveWorkers 正在执行任务的工作线程数
+	ActiveWorkers int
+	// QueueDepth 已提交但还未开始执行的任务数
+	QueueDepth int
+	// Submitted 提交成功的任务总数
+	Submitted uint64
+	// Completed 正常执行完成的任务总数
+	Completed uint64
+	// Failed 执行时发生 panic 的任务总数
+	Failed uint64
+	// Rejected 提交失败的任务总数
+	Rejected uint64
+	// Discarded 提交成功后被丢弃、不会执行的任务总数
+	Discarded uint64
+	// WaitTime 任务从提交到开始执行的累计等待时长
+	WaitTime time.Duration
+	// RunTime 任务的累计执行时长
+	RunTime time.Duration
+}
+
+// StatsGofer 是能够提供统计快照的 Gofer 扩展接口
+type StatsGofer interface {
+	Gofer
+
+	// Stats 返回当前的统计快照
+	Stats() Stats
+}
+
+// Hooks 是执行器生命周期中的回调，为 nil 的回调会被忽略，回调必须是并发安全的
+type Hooks struct {
+	// BeforeExecute 在任务开始执行前调用
+	// wait: 任务从提交到开始执行的等待时长
+	BeforeExecute func(wait time.Duration)
+
+	// AfterExecute 在任务执行结束后调用
+	// run: 任务的执行时长
+	// panicked: 任务是否发生了 panic
+	AfterExecute func(run time.Duration, panicked bool)
+
+	// OnReject 在任务提交失败时调用
+	// err: 提交失败的原因
+	OnReject func(err error)
+
+	// OnPanic 在任务发生 panic 时调用
+	OnPanic func(p any, stack []byte)
+
+	// OnWorkerStart 在工作线程启动时调用
+	OnWorkerStart func()
+
+	// OnWorkerExit 在工作线程退出时调用
+	OnWorkerExit func()
+}
+
+var _ StatsGofer = (*Instrumented)(nil)
+var _ ContextGofer = (*Instrumented)(nil)
+var _ DiscardGofer = (*Instrumented)(nil)
+
+// Instrumented 为任意 Gofer 实现提供统计快照和回调
+// 由于无法观察被包装执行器内部的工作线程，CoreWorkers、EdgeWorkers 始终为 0，OnWorkerStart、OnWorkerExit 不会被调用
+// 通过 GoContext 提交、因 ctx 结束而被跳过的任务计入 Submitted，但不计入 Completed 和 Failed
+// 通过 Go 和 GoDiscard 提交的任务使用 gofer.GoDiscard 提交，被包装的执行器丢弃的任务计入 Discarded
+type Instrumented struct {
+	// Gofer 被包装的执行器
+	Gofer Gofer
+
+	hooks     Hooks
+	pending   atomic.Int64
+	active    atomic.Int64
+	submitted atomic.Uint64
+	completed atomic.Uint64
+	failed    atomic.Uint64
+	rejected  atomic.Uint64
+	discarded atomic.Uint64
+	waitTime  atomic.Int64
+	runTime   atomic.Int64
+}
+
+// Instrument 包装 g，为其提供统计快照和回调
+// g: 被包装的执行器
+// hooks: 生命周期回调
+// 任务中的 panic 在统计和回调之后会被重新抛出，交给 g 自身的机制处理
+func Instrument(g Gofer, hooks Hooks) *Instrumented {
+	return &Instrumented{Gofer: g, hooks: hooks}
+}
+
+// Go 通过被包装的执行器启动一个异步任务
+func (i *Instrumented) Go(f func()) error {
+	return i.GoDiscard(f, nil)
+}
+
+// GoDiscard 通过 gofer.GoDiscard 在被包装的执行器中启动一个异步任务，任务被丢弃时调用 onDiscard
+func (i *Instrumented) GoDiscard(f func(), onDiscard func(err error)) error {
+	return i.submit(nil, func(run func(func()), discard func()) error {
+		return GoDiscard(i.Gofer, func() { run(f) }, func(err error) {
+			discard()
+			if onDiscard != nil {
+				onDiscard(err)
+			}
+		})
+	})
+}
+
+// GoContext 通过 gofer.GoContext 在被包装的执行器中启动一个可感知上下文的任务
+func (i *Instrumented) GoContext(ctx context.Context, f func(ctx context.Context)) error {
+	return i.submit(ctx, func(run func(func()), discard func()) error {
+		return GoContext(ctx, i.Gofer, func(ctx context.Context) {
+			run(func() { f(ctx) })
+		})
+	})
+}
+
+// Close 关闭被包装的执行器
+func (i *Instrumented) Close(ctx context.Context) error {
+	return i.Gofer.Close(ctx)
+}
+
+// Stats 返回当前的统计快照
+func (i *Instrumented) Stats() Stats {
+	return Stats{
+		ActiveWorkers: int(i.active.Load()),
+		QueueDepth:    int(i.pending.Load()),
+		Submitted:     i.submitted.Load(),
+		Completed:     i.completed.Load(),
+		Failed:        i.failed.Load(),
+		Rejected:      i.rejected.Load(),
+		Discarded:     i.discarded.Load(),
+		WaitTime:      time.Duration(i.waitTime.Load()),
+		RunTime:       time.Duration(i.runTime.Load()),
+	}
+}
+
+// 任务的状态
+const (
+	taskPending int32 = iota
+	taskStarted
+	taskSkipped
+)
+
+// submit 使用 goFunc 提交任务，goFunc 提交的任务需要通过 run 执行，任务被丢弃时需要调用 discard
+// ctx 不为 nil 时，任务在开始执行前 ctx 结束的，视为被跳过
+func (i *Instrumented) submit(ctx context.Context, goFunc func(run func(func()), discard func()) error) error {
+	submitted := time.Now()
+	var state atomic.Int32
+	skip := func() {
+		if state.CompareAndSwap(taskPending, taskSkipped) {
+			i.pending.Add(-1)
+		}
+	}
+	i.pending.Add(1)
+	i.submitted.Add(1)
+	stop := func() bool { return false }
+	if ctx != nil {
+		stop = context.AfterFunc(ctx, skip)
+	}
+	err := goFunc(func(f func()) {
+		stop()
+		if !state.CompareAndSwap(taskPending, taskStarted) {
+			return
+		}
+		i.pending.Add(-1)
+		i.execute(submitted, f)
+	}, func() {
+		stop()
+		if state.CompareAndSwap(taskPending, taskSkipped) {
+			i.pending.Add(-1)
+			i.discarded.Add(1)
+		}
+	})
+	if err != nil {
+		stop()
+		skip()
+		i.submitted.Add(^uint64(0))
+		i.rejected.Add(1)
+		if i.hooks.OnReject != nil {
+			i.hooks.OnReject(err)
+		}
+	}
+	return err
+}
+
+// execute 执行任务并记录统计数据，任务中的 panic 会被重新抛出
+func (i *Instrumented) execute(submitted time.Time, f func()) {
+	wait := time.Since(submitted)
+	i.waitTime.Add(int64(wait))
+	if i.hooks.BeforeExecute != nil {
+		i.hooks.BeforeExecute(wait)
+	}
+	i.active.Add(1)
+	start := time.Now()
+	panicked := true
+	defer func() {
+		run := time.Since(start)
+		i.runTime.Add(int64(run))
+		i.active.Add(-1)
+		if panicked {
+			i.failed.Add(1)
+		} else {
+			i.completed.Add(1)
+		}
+		if i.hooks.AfterExecute != nil {
+			i.hooks.AfterExecute(run, panicked)
+		}
+	}()
+	defer func() {
+		if !panicked {
+			return
+		}
+		p := recover()
+		if p == nil {
+			// runtime.Goexit
+			return
+		}
+		if i.hooks.OnPanic != nil {
+			i.hooks.OnPanic(p, debug.Stack())
+		}
+		panic(p)
+	}()
+	f()
+	panicked = false
+}
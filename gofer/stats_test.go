@@ -0,0 +1,110 @@
+package gofer
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInstrument(t *testing.T) {
+	var before, after, panics atomic.Int32
+	q := &queueGofer{}
+	g := Instrument(q, Hooks{
+		BeforeExecute: func(wait time.Duration) { before.Add(1) },
+		AfterExecute:  func(run time.Duration, panicked bool) { after.Add(1) },
+		OnPanic:       func(p any, stack []byte) { panics.Add(1) },
+	})
+	if err := g.Go(func() {}); err != nil {
+		t.Fatalf("Go() returned unexpected error: %v", err)
+	}
+	if err := g.Go(func() { panic("oops") }); err != nil {
+		t.Fatalf("Go() returned unexpected error: %v", err)
+	}
+	if stats := g.Stats(); stats.Submitted != 2 || stats.QueueDepth != 2 {
+		t.Errorf("expected 2 submitted and 2 queued, got %+v", stats)
+	}
+
+	q.tasks[0]()
+	func() {
+		defer func() {
+			if p := recover(); p != "oops" {
+				t.Errorf("expected panic to be re-raised, got %v", p)
+			}
+		}()
+		q.tasks[1]()
+	}()
+
+	stats := g.Stats()
+	if stats.QueueDepth != 0 || stats.ActiveWorkers != 0 || stats.Completed != 1 || stats.Failed != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if before.Load() != 2 || after.Load() != 2 || panics.Load() != 1 {
+		t.Errorf("unexpected hook calls: before=%d after=%d panics=%d", before.Load(), after.Load(), panics.Load())
+	}
+}
+
+func TestInstrument_Reject(t *testing.T) {
+	errFull := errors.New("full")
+	var rejected error
+	g := Instrument(&queueGofer{err: errFull}, Hooks{
+		OnReject: func(err error) { rejected = err },
+	})
+	if err := g.Go(func() {}); !errors.Is(err, errFull) {
+		t.Fatalf("expected %v, got %v", errFull, err)
+	}
+	if !errors.Is(rejected, errFull) {
+		t.Errorf("expected OnReject with %v, got %v", errFull, rejected)
+	}
+	if stats := g.Stats(); stats.Submitted != 0 || stats.Rejected != 1 || stats.QueueDepth != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestInstrument_GoContextSkipped(t *testing.T) {
+	q := &queueGofer{}
+	g := Instrument(q, Hooks{})
+	ctx, cancel := context.WithCancel(context.Background())
+	ran := false
+	if err := g.GoContext(ctx, func(ctx context.Context) { ran = true }); err != nil {
+		t.Fatalf("GoContext() returned unexpected error: %v", err)
+	}
+	if stats := g.Stats(); stats.QueueDepth != 1 {
+		t.Errorf("expected 1 queued task, got %+v", stats)
+	}
+	cancel()
+	// 跳过的任务由 context.AfterFunc 异步移出队列
+	deadline := time.Now().Add(time.Second)
+	for g.Stats().QueueDepth != 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if stats := g.Stats(); stats.QueueDepth != 0 {
+		t.Errorf("expected skipped task to leave the queue, got %+v", stats)
+	}
+	q.tasks[0]()
+	if ran {
+		t.Error("expected task to be skipped")
+	}
+	if stats := g.Stats(); stats.Submitted != 1 || stats.Completed != 0 || stats.Failed != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestInstrument_Discard(t *testing.T) {
+	errDiscarded := errors.New("discarded")
+	g := Instrument(discardGofer{err: errDiscarded}, Hooks{})
+	var discarded error
+	if err := g.GoDiscard(func() {}, func(err error) { discarded = err }); err != nil {
+		t.Fatalf("GoDiscard() returned unexpected error: %v", err)
+	}
+	if err := g.Go(func() {}); err != nil {
+		t.Fatalf("Go() returned unexpected error: %v", err)
+	}
+	if !errors.Is(discarded, errDiscarded) {
+		t.Errorf("expected %v, got %v", errDiscarded, discarded)
+	}
+	if stats := g.Stats(); stats.Submitted != 2 || stats.Discarded != 2 || stats.QueueDepth != 0 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
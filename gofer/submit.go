@@ -0,0 +1,39 @@
+package gofer
+
+import (
+	"context"
+
+	"github.com/soyacen/goconc/brave"
+	"github.com/soyacen/goconc/future"
+)
+
+// Submit 将 f 提交到 g 中执行，返回 f 结果的 Future，适用于任意 Gofer 实现
+// g: 执行任务的执行器
+// f: 要执行的任务函数，f 中的 panic 会被捕获并转换为 Future 的错误
+// 返回 f 结果的 Future；如果 g 拒绝了任务，返回 g.Go 的错误；如果 g 是 DiscardGofer 且丢弃了任务，Future 以丢弃的原因失败
+// 取消 Future 会取消传给 f 的上下文；如果任务还未开始执行，f 不会被调用
+func Submit[T any](g Gofer, f func(ctx context.Context) (T, error)) (*future.Future[T], error) {
+	promise, ctx := future.NewPromiseContext[T](context.Background())
+	err := GoDiscard(g, func() {
+		if err := ctx.Err(); err != nil {
+			promise.Reject(err)
+			return
+		}
+		value, err := brave.DoRE(func() (T, error) {
+			return f(ctx)
+		})
+		if err != nil {
+			promise.Reject(err)
+			return
+		}
+		promise.Resolve(value)
+	}, func(err error) {
+		promise.Reject(err)
+	})
+	if err != nil {
+		// 释放 Future 的上下文
+		promise.Reject(err)
+		return nil, err
+	}
+	return promise.Future(), nil
+}
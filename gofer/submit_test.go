@@ -0,0 +1,136 @@
+package gofer
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/soyacen/goconc/future"
+)
+
+// goGofer 为每个任务启动一个协程
+type goGofer struct{}
+
+func (goGofer) Go(f func()) error {
+	go f()
+	return nil
+}
+
+func (goGofer) Close(ctx context.Context) error { return nil }
+
+// queueGofer 只保存任务，由测试手动执行
+type queueGofer struct {
+	tasks []func()
+	err   error
+}
+
+func (g *queueGofer) Go(f func()) error {
+	if g.err != nil {
+		return g.err
+	}
+	g.tasks = append(g.tasks, f)
+	return nil
+}
+
+func (g *queueGofer) Close(ctx context.Context) error { return nil }
+
+// discardGofer 接受任务后立即丢弃
+type discardGofer struct {
+	err error
+}
+
+func (g discardGofer) Go(f func()) error { return g.GoDiscard(f, nil) }
+
+func (g discardGofer) GoDiscard(f func(), onDiscard func(err error)) error {
+	if onDiscard != nil {
+		onDiscard(g.err)
+	}
+	return nil
+}
+
+func (discardGofer) Close(ctx context.Context) error { return nil }
+
+func TestSubmit_Discard(t *testing.T) {
+	errDiscarded := errors.New("discarded")
+	f, err := Submit(discardGofer{err: errDiscarded}, func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+	if err != nil {
+		t.Fatalf("Submit() returned unexpected error: %v", err)
+	}
+	if _, err := f.Get(context.Background()); !errors.Is(err, errDiscarded) {
+		t.Errorf("expected %v, got %v", errDiscarded, err)
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	f, err := Submit(goGofer{}, func(ctx context.Context) (int, error) {
+		return 42, nil
+	})
+	if err != nil {
+		t.Fatalf("Submit() returned unexpected error: %v", err)
+	}
+	if value, err := f.Get(context.Background()); err != nil || value != 42 {
+		t.Errorf("expected 42, got %v, %v", value, err)
+	}
+}
+
+func TestSubmit_Panic(t *testing.T) {
+	f, err := Submit(goGofer{}, func(ctx context.Context) (int, error) {
+		panic("oops")
+	})
+	if err != nil {
+		t.Fatalf("Submit() returned unexpected error: %v", err)
+	}
+	if _, err := f.Get(context.Background()); err == nil || !strings.Contains(err.Error(), "oops") {
+		t.Errorf("expected panic error, got %v", err)
+	}
+}
+
+func TestSubmit_Rejected(t *testing.T) {
+	errFull := errors.New("full")
+	f, err := Submit(&queueGofer{err: errFull}, func(ctx context.Context) (int, error) {
+		return 0, nil
+	})
+	if !errors.Is(err, errFull) || f != nil {
+		t.Errorf("expected rejection, got %v, %v", f, err)
+	}
+}
+
+func TestSubmit_Cancel(t *testing.T) {
+	g := &queueGofer{}
+	called := false
+	f, err := Submit(g, func(ctx context.Context) (int, error) {
+		called = true
+		return 0, nil
+	})
+	if err != nil {
+		t.Fatalf("Submit() returned unexpected error: %v", err)
+	}
+	f.Cancel()
+	g.tasks[0]()
+	if called {
+		t.Error("canceled task should not be called")
+	}
+	if _, err := f.Get(context.Background()); !errors.Is(err, future.ErrCanceled) {
+		t.Errorf("expected ErrCanceled, got %v", err)
+	}
+}
+
+func TestGoContext_Fallback(t *testing.T) {
+	g := &queueGofer{}
+	ctx, cancel := context.WithCancel(context.Background())
+	called := false
+	if err := GoContext(ctx, g, func(ctx context.Context) { called = true }); err != nil {
+		t.Fatalf("GoContext() returned unexpected error: %v", err)
+	}
+	cancel()
+	g.tasks[0]()
+	if called {
+		t.Error("expired task should be skipped")
+	}
+	if err := GoContext(ctx, g, func(ctx context.Context) {}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
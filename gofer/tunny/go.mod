@@ -1,6 +1,6 @@
 module github.com/soyacen/goconc/gofer/tunny
 
-go 1.20
+go 1.23.0
 
 require (
 	github.com/Jeffail/tunny v0.1.4
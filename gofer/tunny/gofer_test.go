@@ -0,0 +1,62 @@
+package tunny
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Jeffail/tunny"
+	"github.com/soyacen/goconc/gofer"
+)
+
+func TestGofer_Go(t *testing.T) {
+	// 创建tunny池
+	pool := newDefaultTunnyPool()
+	defer pool.Close()
+
+	// tunny.Pool.Process 同步执行任务，Go 返回时任务已经执行完毕
+	executed := false
+	if err := (&Gofer{Pool: pool}).Go(func() { executed = true }); err != nil {
+		t.Fatalf("Go() returned unexpected error: %v", err)
+	}
+	if !executed {
+		t.Error("expected task to be executed before Go returns")
+	}
+}
+
+func TestGofer_Submit(t *testing.T) {
+	// 创建tunny池
+	pool := newDefaultTunnyPool()
+	defer pool.Close()
+
+	g := &Gofer{Pool: pool}
+	f, err := gofer.Submit(g, func(ctx context.Context) (string, error) {
+		return "done", nil
+	})
+	if err != nil {
+		t.Fatalf("Submit() returned unexpected error: %v", err)
+	}
+	value, err := f.Get(context.Background())
+	if err != nil || value != "done" {
+		t.Errorf("expected done, got %v, %v", value, err)
+	}
+
+	errBoom := errors.New("boom")
+	f, err = gofer.Submit(g, func(ctx context.Context) (string, error) {
+		return "", errBoom
+	})
+	if err != nil {
+		t.Fatalf("Submit() returned unexpected error: %v", err)
+	}
+	if _, err := f.Get(context.Background()); !errors.Is(err, errBoom) {
+		t.Errorf("expected %v, got %v", errBoom, err)
+	}
+}
+
+// newDefaultTunnyPool 创建默认配置的tunny池用于测试，任务作为 payload 传入并被执行
+func newDefaultTunnyPool() *tunny.Pool {
+	return tunny.NewFunc(2, func(payload any) any {
+		payload.(func())()
+		return nil
+	})
+}
@@ -1,6 +1,6 @@
 module github.com/soyacen/goconc/gofer/tunny
 
-go 1.20
+go 1.23.0
 
 require (
 	github.com/gammazero/workerpool v1.1.3
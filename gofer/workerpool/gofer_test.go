@@ -0,0 +1,44 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gammazero/workerpool"
+	"github.com/soyacen/goconc/gofer"
+)
+
+func TestGofer_Submit(t *testing.T) {
+	// 创建worker池
+	pool := newDefaultWorkerPool()
+	defer pool.StopWait()
+
+	g := &Gofer{Pool: pool}
+	f, err := gofer.Submit(g, func(ctx context.Context) (string, error) {
+		return "done", nil
+	})
+	if err != nil {
+		t.Fatalf("Submit() returned unexpected error: %v", err)
+	}
+	value, err := f.Get(context.Background())
+	if err != nil || value != "done" {
+		t.Errorf("expected done, got %v, %v", value, err)
+	}
+
+	errBoom := errors.New("boom")
+	f, err = gofer.Submit(g, func(ctx context.Context) (string, error) {
+		return "", errBoom
+	})
+	if err != nil {
+		t.Fatalf("Submit() returned unexpected error: %v", err)
+	}
+	if _, err := f.Get(context.Background()); !errors.Is(err, errBoom) {
+		t.Errorf("expected %v, got %v", errBoom, err)
+	}
+}
+
+// newDefaultWorkerPool 创建默认配置的worker池用于测试
+func newDefaultWorkerPool() *workerpool.WorkerPool {
+	return workerpool.New(2)
+}